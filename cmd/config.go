@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"music-library/config"
+	"os"
+)
+
+// runConfigCommand выполняет команду работы с конфигурацией:
+//
+//	config print — вывести итоговую конфигурацию в формате YAML (секреты скрыты)
+//
+// Конфигурация выводится, даже если она не прошла проверку, чтобы было видно, какие значения
+// получились из всех источников; ошибки проверки выводятся после нее.
+//
+// Возвращает код завершения процесса.
+func runConfigCommand(cfg *config.Config, loadErr error, args []string) int {
+	if len(args) != 1 || args[0] != "print" {
+		printConfigUsage()
+		return 2
+	}
+
+	if err := cfg.Print(os.Stdout); err != nil {
+		log.Printf("ERROR: Failed to print configuration: %v", err)
+		return 1
+	}
+	if loadErr != nil {
+		logConfigErrors(loadErr)
+		return 1
+	}
+	return 0
+}
+
+// logConfigErrors выводит каждую ошибку конфигурации отдельной строкой.
+func logConfigErrors(err error) {
+	for _, e := range unwrapAll(err) {
+		log.Printf("ERROR: Invalid configuration: %v", e)
+	}
+}
+
+// unwrapAll раскрывает ошибки, объединенные через errors.Join, в плоский список.
+func unwrapAll(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, unwrapAll(e)...)
+	}
+	return errs
+}
+
+func printConfigUsage() {
+	fmt.Fprintln(os.Stderr, "usage: music-library config print")
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"github.com/gin-gonic/gin"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
@@ -9,7 +12,16 @@ import (
 	"music-library/controllers"
 	"music-library/database"
 	_ "music-library/docs"
-	"net/http"
+	"music-library/jobs"
+	"music-library/metrics"
+	"music-library/providers"
+	"music-library/repository"
+	"music-library/server"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 )
 
 // @title Music Library API
@@ -19,71 +31,194 @@ import (
 // @BasePath /
 
 func main() {
-	// 1. Загрузка переменных окружения из .env файла
-	config.LoadEnv()
-	log.Println("INFO: Environment variables loaded.")
+	os.Exit(run())
+}
+
+// run запускает приложение и возвращает код завершения процесса: 0 — штатная остановка,
+// 1 — ошибка запуска или работы одного из компонентов, 2 — ошибка конфигурации.
+//
+// При SIGINT или SIGTERM компоненты останавливаются в порядке, обратном запуску: HTTP-сервер
+// дожидается обрабатываемых запросов, затем останавливаются фоновые задачи, последним
+// закрывается пул соединений с базой данных. Повторный сигнал завершает процесс немедленно.
+func run() (code int) {
+	// 1. Загрузка конфигурации: значения по умолчанию, файл, переменные окружения и флаги
+	cfg, args, err := config.Load(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
 
-	// 2. Подключение к базе данных
-	db := database.Connect()
+	// Команда вывода конфигурации: config print (работает и с ошибками в конфигурации)
+	if len(args) > 0 && args[0] == "config" {
+		return runConfigCommand(cfg, err, args[1:])
+	}
+	if err != nil {
+		logConfigErrors(err)
+		return 2
+	}
+	log.Println("INFO: Configuration loaded.")
+
+	// Остановка по сигналу; после первого сигнала обработка сигналов возвращается к стандартной
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	// 2. Подключение к базе данных; пул закрывается последним
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return 1
+	}
+	defer func() {
+		if err := database.Close(db); err != nil {
+			log.Printf("ERROR: %v", err)
+			code = 1
+		}
+	}()
 	log.Println("INFO: Database connection established.")
 
-	// 3. Выполнение миграций базы данных (создание таблиц, если их нет)
-	database.Migrate(db)
+	// Команда управления миграциями: migrate up | down | to N | status
+	if len(args) > 0 && args[0] == "migrate" {
+		return runMigrateCommand(db, args[1:])
+	}
+
+	// 3. Выполнение миграций базы данных; запуск невозможен, если схема новее сборки
+	if err := database.Migrate(db); err != nil {
+		log.Printf("ERROR: Database migrations failed: %v", err)
+		return 1
+	}
 	log.Println("INFO: Database migrations completed.")
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return 1
+	}
 
-	// 4. Инициализация HTTP-сервера с помощью Gin
-	router := gin.Default()
+	// Метрики запросов к базе данных, пула соединений и количества песен
+	if err := metrics.InstrumentDB(db); err != nil {
+		log.Printf("ERROR: Failed to instrument the database: %v", err)
+		return 1
+	}
 
-	// 5. Определение маршрутов основного API
-	router.GET("/info", controllers.GetSongInfo)                           // Получение информации о песне
-	router.GET("/songs", controllers.GetSongs)                             // Получение списка всех песен
-	router.GET("/songs/:id/verses", controllers.GetSongTextWithPagination) // Получение текста песни с пагинацией
-	router.PUT("/songs/:id", controllers.UpdateSong)                       // Обновление информации о песне по ID
-	router.DELETE("/songs/:id", controllers.DeleteSong)                    // Удаление песни по ID
+	// Фоновые задачи работают в собственном контексте: они останавливаются только после
+	// HTTP-сервера, чтобы не прерывать обрабатываемые запросы
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	defer func() {
+		stopWorkers()
+		workers.Wait()
+		log.Println("INFO: Background workers stopped.")
+	}()
+
+	// 4. Настройка цепочки источников информации о песнях: локальный файл, затем внешний API.
+	// Файл перечитывается автоматически при изменении на диске.
+	fileProvider := providers.NewFileProvider(cfg.Enrichment.File)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		fileProvider.Watch(workersCtx, cfg.Enrichment.FileReloadInterval)
+	}()
+	upstream := providers.NewHTTPProvider(cfg.Enrichment.API)
+	enrichment := providers.NewChain(fileProvider, upstream)
+
+	// 5. Фоновая очистка корзины от песен старше срока хранения
+	songStore := &repository.SongRepository{DB: db}
+	purger := &jobs.TrashPurger{
+		Repo:      songStore,
+		Retention: cfg.Trash.Retention,
+		Interval:  cfg.Trash.PurgeInterval,
+	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		purger.Run(workersCtx)
+	}()
 
-	// 6. Swagger-документация доступна по адресу http://localhost:8080/swagger/index.html
+	// 6. Инициализация HTTP-сервера с помощью Gin
+	router := gin.Default()
+	router.Use(metrics.Middleware()) // Метрики количества и времени обработки запросов
+
+	// 7. Создание обработчиков с их зависимостями и определение маршрутов основного API
+	songs := controllers.NewSongHandler(songStore, enrichment, cfg.Pagination, cfg.Fuzzy)
+	artists := controllers.NewArtistHandler(&repository.ArtistRepository{DB: db}, cfg.Pagination)
+	albums := controllers.NewAlbumHandler(&repository.AlbumRepository{DB: db}, cfg.Pagination)
+	genres := controllers.NewGenreHandler(&repository.GenreRepository{DB: db})
+	tags := controllers.NewTagHandler(&repository.TagRepository{DB: db}, cfg.Pagination)
+	playlists := controllers.NewPlaylistHandler(&repository.PlaylistRepository{DB: db}, cfg.Pagination)
+	search := controllers.NewSearchHandler(&repository.SearchRepository{DB: db}, cfg.Pagination, cfg.Search)
+	health := controllers.NewHealthHandler(db, migrator, upstream, buildInfo())
+
+	router.GET("/healthz", health.Healthz)               // Процесс запущен
+	router.GET("/readyz", health.Readyz)                 // Сервис готов принимать запросы
+	router.GET("/status", health.Status)                 // Подробное состояние зависимостей, пула соединений и сборки
+	router.GET("/metrics", gin.WrapH(metrics.Handler())) // Метрики в формате Prometheus
+
+	router.GET("/info", songs.GetSongInfo)                           // Получение информации о песне
+	router.GET("/songs", songs.GetSongs)                             // Получение списка всех песен
+	router.GET("/search", search.Search)                             // Полнотекстовый поиск по песням
+	router.POST("/songs", songs.CreateSong)                          // Создание новой песни
+	router.GET("/songs/:id/verses", songs.GetSongTextWithPagination) // Получение текста песни с пагинацией
+	router.PUT("/songs/:id", songs.UpdateSong)                       // Обновление информации о песне по ID
+	router.DELETE("/songs/:id", songs.DeleteSong)                    // Перемещение песни в корзину по ID
+	router.GET("/songs/trash", songs.GetTrashedSongs)                // Список песен в корзине
+	router.POST("/songs/:id/restore", songs.RestoreSong)             // Восстановление песни из корзины
+	router.DELETE("/songs/:id/purge", songs.PurgeSong)               // Окончательное удаление песни из корзины
+
+	router.GET("/artists", artists.GetArtists)               // Список исполнителей
+	router.POST("/artists", artists.CreateArtist)            // Создание исполнителя
+	router.GET("/artists/:id", artists.GetArtist)            // Получение исполнителя по ID
+	router.PUT("/artists/:id", artists.UpdateArtist)         // Обновление исполнителя по ID
+	router.DELETE("/artists/:id", artists.DeleteArtist)      // Удаление исполнителя без песен и альбомов
+	router.GET("/artists/:id/songs", artists.GetArtistSongs) // Песни исполнителя
+
+	router.GET("/albums", albums.GetAlbums)                               // Список альбомов
+	router.POST("/albums", albums.CreateAlbum)                            // Создание альбома
+	router.GET("/albums/:id", albums.GetAlbum)                            // Получение альбома по ID
+	router.PUT("/albums/:id", albums.UpdateAlbum)                         // Обновление альбома по ID
+	router.DELETE("/albums/:id", albums.DeleteAlbum)                      // Удаление альбома (песни остаются)
+	router.GET("/albums/:id/tracks", albums.GetAlbumTracks)               // Трек-лист альбома
+	router.PUT("/albums/:id/tracks/:song_id", albums.AttachAlbumTrack)    // Добавление песни в альбом
+	router.DELETE("/albums/:id/tracks/:song_id", albums.DetachAlbumTrack) // Удаление песни из альбома
+
+	router.GET("/genres", genres.GetGenres)                   // Список жанров
+	router.POST("/genres", genres.CreateGenre)                // Создание жанра
+	router.GET("/genres/:id", genres.GetGenre)                // Получение жанра по ID
+	router.PUT("/genres/:id", genres.UpdateGenre)             // Обновление жанра (в том числе перенос в другую ветку)
+	router.DELETE("/genres/:id", genres.DeleteGenre)          // Удаление жанра без поджанров
+	router.PUT("/songs/:id/genres", genres.SetSongGenres)     // Замена набора жанров песни
+	router.GET("/tags", tags.GetTags)                         // Список тегов
+	router.DELETE("/tags/:id", tags.DeleteTag)                // Удаление тега у всех песен
+	router.PUT("/songs/:id/tags", tags.SetSongTags)           // Замена набора тегов песни
+	router.POST("/songs/:id/tags", tags.AddSongTags)          // Добавление тегов песне
+	router.DELETE("/songs/:id/tags/:tag", tags.RemoveSongTag) // Снятие тега с песни
+
+	router.GET("/playlists", playlists.GetPlaylists)                                 // Список плейлистов
+	router.POST("/playlists", playlists.CreatePlaylist)                              // Создание плейлиста
+	router.GET("/playlists/:id", playlists.GetPlaylist)                              // Плейлист с записями
+	router.PUT("/playlists/:id", playlists.UpdatePlaylist)                           // Обновление плейлиста
+	router.DELETE("/playlists/:id", playlists.DeletePlaylist)                        // Удаление плейлиста
+	router.POST("/playlists/:id/duplicate", playlists.DuplicatePlaylist)             // Копирование плейлиста
+	router.POST("/playlists/:id/entries", playlists.AddPlaylistEntry)                // Добавление песни в плейлист
+	router.PUT("/playlists/:id/entries/:entry_id", playlists.MovePlaylistEntry)      // Перемещение записи
+	router.DELETE("/playlists/:id/entries/:entry_id", playlists.RemovePlaylistEntry) // Удаление записи
+
+	// 8. Swagger-документация доступна по адресу /swagger/index.html
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	log.Println("INFO: Swagger documentation is available at http://localhost:8080/swagger/index.html")
+	log.Printf("INFO: Swagger documentation is available at http://%s/swagger/index.html", swaggerHost(cfg.HTTP.Addr))
 
-	// 7. Запуск второго сервера на порту 8081 для эмуляции внешнего API
-	go startMockServer()
-
-	// 8. Запуск основного сервера на порту 8080
-	log.Println("INFO: Starting the main server on port 8080...")
-	log.Fatal(router.Run(":8080")) // Запуск основного HTTP-сервера и логирование фатальных ошибок
+	// 9. Запуск основного сервера на адресе из http.addr; блокируется до сигнала остановки
+	log.Printf("INFO: Starting the main server on %s...", cfg.HTTP.Addr)
+	if err := server.Run(ctx, server.New(cfg.HTTP, router), cfg.HTTP.ShutdownTimeout); err != nil {
+		log.Printf("ERROR: %v", err)
+		return 1
+	}
+	return 0
 }
 
-// startMockServer запускает тестовый сервер на порту 8081 для эмуляции внешнего API
-func startMockServer() {
-	testRouter := gin.Default()
-
-	// 1. Определяем маршрут для получения информации о песне из внешнего JSON
-	testRouter.GET("/info", func(c *gin.Context) {
-		group := c.Query("group") // Получаем параметр "group" из URL
-		song := c.Query("song")   // Получаем параметр "song" из URL
-
-		// 2. Проверка параметров запроса
-		if group == "" || song == "" {
-			log.Println("DEBUG: Missing request parameters: group or song.")
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing parameters"})
-			return
-		}
-
-		// 3. Получение информации о песне из JSON
-		songDetail, err := controllers.GetSongDetailFromJSON(group, song)
-		if err != nil {
-			log.Printf("DEBUG: Error fetching song details: %v\n", err)
-			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
-			return
-		}
-
-		// 4. Успешный ответ с информацией о песне
-		log.Printf("INFO: Request to /info succeeded for group: %s, song: %s\n", group, song)
-		c.JSON(http.StatusOK, songDetail)
-	})
-
-	// 5. Запуск тестового сервера на порту 8081
-	if err := testRouter.Run(":8081"); err != nil {
-		log.Fatalf("ERROR: Failed to start the test server: %v", err)
+// swaggerHost возвращает адрес для ссылки на документацию: пустой хост заменяется на localhost.
+func swaggerHost(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
 	}
+	return net.JoinHostPort(host, port)
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"log"
+	"music-library/database"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// runMigrateCommand выполняет команду управления миграциями:
+//
+//	migrate up       — применить все миграции
+//	migrate down     — откатить последнюю миграцию
+//	migrate to N     — привести схему к версии N (0 — откатить все)
+//	migrate status   — показать состояние миграций
+//
+// Возвращает код завершения процесса.
+func runMigrateCommand(db *gorm.DB, args []string) int {
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return 1
+	}
+	if len(args) == 0 {
+		printMigrateUsage()
+		return 2
+	}
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "to":
+		if len(args) != 2 {
+			printMigrateUsage()
+			return 2
+		}
+		target, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			log.Printf("ERROR: Invalid target version %q", args[1])
+			return 2
+		}
+		err = migrator.To(target)
+	case "status":
+		err = printMigrationStatus(migrator)
+	default:
+		printMigrateUsage()
+		return 2
+	}
+
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return 1
+	}
+	if args[0] != "status" {
+		current, err := migrator.Current()
+		if err != nil {
+			log.Printf("ERROR: %v", err)
+			return 1
+		}
+		log.Printf("INFO: Schema is at version %d (latest known: %d)", current, migrator.Latest())
+	}
+	return 0
+}
+
+// printMigrationStatus выводит таблицу состояния миграций
+func printMigrationStatus(migrator *database.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Modified {
+			state += " (modified)"
+		}
+		if status.Unknown {
+			state += " (unknown to this binary)"
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return writer.Flush()
+}
+
+// printMigrateUsage выводит справку по команде migrate
+func printMigrateUsage() {
+	fmt.Fprintln(os.Stderr, "usage: music-library migrate up | down | to <version> | status")
+}
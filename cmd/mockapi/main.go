@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/gin-gonic/gin"
+	"log"
+	"music-library/providers"
+	"music-library/server"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Мок внешнего API с информацией о песнях (GET /info?group=...&song=...).
+//
+// Используется в локальной разработке и интеграционных тестах вместо настоящего сервиса.
+// Данные берутся из файлов фикстур (формат как у song_enrichment.json), а сценарий
+// позволяет добавлять задержки, случайные ошибки и заданные коды ответа для конкретных песен.
+//
+// Служебные маршруты:
+//   - GET /__mock/requests — журнал полученных запросов;
+//   - DELETE /__mock/requests — очистка журнала;
+//   - GET /__mock/scenario — текущий сценарий;
+//   - PUT /__mock/scenario — замена сценария (тело в формате файла сценария).
+func main() {
+	addr := flag.String("addr", ":8081", "адрес, на котором слушает мок-сервер")
+	fixtures := flag.String("fixtures", "song_enrichment.json", "файлы фикстур через запятую (первый имеет наивысший приоритет)")
+	scenarioPath := flag.String("scenario", "", "JSON-файл сценария поведения")
+	latency := flag.Duration("latency", 0, "базовая задержка ответа (переопределяет сценарий)")
+	errorRate := flag.Float64("error-rate", -1, "доля ответов с ошибкой 0..1 (переопределяет сценарий)")
+	reloadInterval := flag.Duration("reload", 2*time.Second, "интервал проверки изменений файлов фикстур")
+	recordLimit := flag.Int("record-limit", 1000, "сколько последних запросов хранить в журнале")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 5*time.Second, "время на завершение обрабатываемых запросов при остановке")
+	flag.Parse()
+
+	// Остановка по SIGINT или SIGTERM; повторный сигнал завершает процесс немедленно
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	// 1. Загрузка фикстур: каждый файл — отдельный источник, файлы перечитываются при изменении
+	var sources []providers.EnrichmentProvider
+	for _, path := range strings.Split(*fixtures, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		fileProvider := providers.NewFileProvider(path)
+		go fileProvider.Watch(ctx, *reloadInterval)
+		sources = append(sources, fileProvider)
+	}
+	fixtureSource := providers.NewChain(sources...)
+
+	// 2. Загрузка сценария и переопределений из флагов
+	var scenario Scenario
+	if *scenarioPath != "" {
+		var err error
+		if scenario, err = loadScenario(*scenarioPath); err != nil {
+			log.Fatalf("ERROR: Failed to load scenario: %v", err)
+		}
+	}
+	if *latency > 0 {
+		scenario.Latency = duration(*latency)
+	}
+	if *errorRate >= 0 {
+		scenario.ErrorRate = *errorRate
+	}
+	if err := scenario.validate(); err != nil {
+		log.Fatalf("ERROR: Invalid scenario: %v", err)
+	}
+
+	behaviour := NewBehaviour(scenario)
+	recorder := NewRecorder(*recordLimit)
+
+	// 3. Маршруты мок-сервера
+	router := gin.Default()
+	router.GET("/info", infoHandler(fixtureSource, behaviour, recorder))
+
+	router.GET("/__mock/requests", func(c *gin.Context) {
+		c.JSON(http.StatusOK, recorder.List())
+	})
+	router.DELETE("/__mock/requests", func(c *gin.Context) {
+		recorder.Reset()
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/__mock/scenario", func(c *gin.Context) {
+		c.JSON(http.StatusOK, behaviour.Get())
+	})
+	router.PUT("/__mock/scenario", func(c *gin.Context) {
+		var next Scenario
+		if err := c.ShouldBindJSON(&next); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := next.validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		behaviour.Set(next)
+		log.Printf("INFO: Mock scenario replaced: %d rules", len(next.Rules))
+		c.JSON(http.StatusOK, next)
+	})
+
+	// 4. Запуск сервера; блокируется до сигнала остановки. Таймаутов записи нет:
+	// сценарий может задерживать ответы сколь угодно долго.
+	log.Printf("INFO: Starting the mock song-info API on %s...", *addr)
+	srv := &http.Server{Addr: *addr, Handler: router, ReadHeaderTimeout: 10 * time.Second}
+	if err := server.Run(ctx, srv, *shutdownTimeout); err != nil {
+		log.Printf("ERROR: Mock server failed: %v", err)
+		os.Exit(1)
+	}
+}
+
+// infoHandler эмулирует GET /info внешнего API с учетом сценария и записывает запросы в журнал
+func infoHandler(source providers.EnrichmentProvider, behaviour *Behaviour, recorder *Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group := c.Query("group") // Получаем параметр "group" из URL
+		song := c.Query("song")   // Получаем параметр "song" из URL
+
+		record := RecordedRequest{
+			Time:    time.Now(),
+			Method:  c.Request.Method,
+			Path:    c.Request.URL.Path,
+			Query:   c.Request.URL.RawQuery,
+			Group:   group,
+			Song:    song,
+			Headers: make(map[string]string),
+		}
+		for name := range c.Request.Header {
+			record.Headers[name] = c.Request.Header.Get(name)
+		}
+		defer func() {
+			record.Status = c.Writer.Status()
+			recorder.Add(record)
+		}()
+
+		// 1. Проверка параметров запроса
+		if group == "" || song == "" {
+			log.Println("DEBUG: Missing request parameters: group or song.")
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing parameters"})
+			return
+		}
+
+		// 2. Задержка и сценарные ошибки
+		outcome := behaviour.Decide(group, song)
+		record.Delay = outcome.Delay.String()
+		if outcome.Delay > 0 {
+			select {
+			case <-time.After(outcome.Delay):
+			case <-c.Request.Context().Done():
+				log.Printf("DEBUG: Client went away while the response for '%s' by '%s' was delayed", song, group)
+				return
+			}
+		}
+		if outcome.Status != 0 {
+			if outcome.RetryAfter != "" {
+				c.Header("Retry-After", outcome.RetryAfter)
+			}
+			body := outcome.Body
+			if body == "" {
+				body = http.StatusText(outcome.Status)
+			}
+			log.Printf("INFO: Scripted response %d for group: %s, song: %s", outcome.Status, group, song)
+			c.String(outcome.Status, body)
+			return
+		}
+
+		// 3. Получение информации о песне из фикстур
+		songDetail, err := source.FetchSongDetail(c.Request.Context(), group, song)
+		if err != nil {
+			log.Printf("DEBUG: Error fetching song details: %v\n", err)
+			c.JSON(http.StatusNotFound, gin.H{"error": "song not found"})
+			return
+		}
+
+		// 4. Успешный ответ с информацией о песне (без служебного поля sources)
+		record.Matched = true
+		songDetail.Sources = nil
+		log.Printf("INFO: Request to /info succeeded for group: %s, song: %s\n", group, song)
+		c.JSON(http.StatusOK, songDetail)
+	}
+}
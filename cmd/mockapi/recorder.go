@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// RecordedRequest — запрос, полученный мок-сервером.
+type RecordedRequest struct {
+	Time    time.Time         `json:"time"`
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query"`
+	Group   string            `json:"group"`
+	Song    string            `json:"song"`
+	Headers map[string]string `json:"headers"`
+	Status  int               `json:"status"`  // Отправленный код ответа
+	Delay   string            `json:"delay"`   // Внесенная задержка
+	Matched bool              `json:"matched"` // Найдена ли песня в фикстурах
+}
+
+// Recorder хранит последние полученные запросы.
+type Recorder struct {
+	mu       sync.Mutex
+	limit    int
+	requests []RecordedRequest
+}
+
+// NewRecorder создает журнал, хранящий не более limit последних запросов.
+func NewRecorder(limit int) *Recorder {
+	return &Recorder{limit: limit}
+}
+
+// Add добавляет запрос в журнал, вытесняя самые старые записи при переполнении.
+func (r *Recorder) Add(request RecordedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, request)
+	if r.limit > 0 && len(r.requests) > r.limit {
+		r.requests = r.requests[len(r.requests)-r.limit:]
+	}
+}
+
+// List возвращает копию журнала.
+func (r *Recorder) List() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]RecordedRequest{}, r.requests...)
+}
+
+// Reset очищает журнал.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = nil
+}
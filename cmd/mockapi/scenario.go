@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// duration — time.Duration, которая читается из JSON в виде строки ("150ms", "2s").
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("duration must be a string like \"150ms\": %w", err)
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+	*d = duration(value)
+	return nil
+}
+
+func (d duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Rule описывает поведение мок-сервера для конкретной песни (или для всех песен группы).
+type Rule struct {
+	Group      string   `json:"group,omitempty"`       // Группа (пусто — любая)
+	Song       string   `json:"song,omitempty"`        // Песня (пусто — любая)
+	Status     int      `json:"status,omitempty"`      // Код ответа (0 — обычный ответ из фикстур)
+	Body       string   `json:"body,omitempty"`        // Тело ответа для Status
+	Latency    duration `json:"latency,omitempty"`     // Дополнительная задержка ответа
+	RetryAfter string   `json:"retry_after,omitempty"` // Значение заголовка Retry-After
+	Times      int      `json:"times,omitempty"`       // Сколько раз применить правило (0 — без ограничений)
+}
+
+// matches проверяет, подходит ли правило для запроса.
+func (r Rule) matches(group, song string) bool {
+	return (r.Group == "" || strings.EqualFold(r.Group, group)) &&
+		(r.Song == "" || strings.EqualFold(r.Song, song))
+}
+
+// Scenario — сценарий поведения мок-сервера.
+type Scenario struct {
+	Latency       duration `json:"latency,omitempty"`        // Базовая задержка каждого ответа
+	LatencyJitter duration `json:"latency_jitter,omitempty"` // Случайная добавка к задержке [0, jitter]
+	ErrorRate     float64  `json:"error_rate,omitempty"`     // Доля запросов, завершающихся ошибкой (0..1)
+	ErrorStatus   int      `json:"error_status,omitempty"`   // Код ответа для случайных ошибок (по умолчанию 500)
+	Rules         []Rule   `json:"rules,omitempty"`          // Правила для конкретных песен, проверяются по порядку
+}
+
+// loadScenario читает сценарий из JSON-файла.
+func loadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+
+	var scenario Scenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return Scenario{}, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	return scenario, scenario.validate()
+}
+
+// validate проверяет корректность значений сценария.
+func (s Scenario) validate() error {
+	if s.ErrorRate < 0 || s.ErrorRate > 1 {
+		return fmt.Errorf("error_rate must be between 0 and 1")
+	}
+	for i, rule := range s.Rules {
+		if rule.Status != 0 && (rule.Status < 100 || rule.Status > 599) {
+			return fmt.Errorf("rule %d: invalid status %d", i+1, rule.Status)
+		}
+		if rule.Times < 0 {
+			return fmt.Errorf("rule %d: times must not be negative", i+1)
+		}
+	}
+	return nil
+}
+
+// Outcome — решение мок-сервера о том, как ответить на запрос.
+type Outcome struct {
+	Delay      time.Duration
+	Status     int    // 0 — обычный ответ из фикстур
+	Body       string // Тело ответа для Status
+	RetryAfter string
+}
+
+// Behaviour хранит текущий сценарий и счетчики срабатывания правил.
+type Behaviour struct {
+	mu       sync.Mutex
+	scenario Scenario
+	used     []int // Сколько раз сработало каждое правило
+	random   *rand.Rand
+}
+
+// NewBehaviour создает поведение по сценарию.
+func NewBehaviour(scenario Scenario) *Behaviour {
+	b := &Behaviour{random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	b.Set(scenario)
+	return b
+}
+
+// Set заменяет сценарий и сбрасывает счетчики правил.
+func (b *Behaviour) Set(scenario Scenario) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.scenario = scenario
+	b.used = make([]int, len(scenario.Rules))
+}
+
+// Get возвращает текущий сценарий.
+func (b *Behaviour) Get() Scenario {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.scenario
+}
+
+// Decide выбирает ответ для запроса: сначала подходящее правило, затем случайная ошибка.
+func (b *Behaviour) Decide(group, song string) Outcome {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	outcome := Outcome{Delay: time.Duration(b.scenario.Latency)}
+	if jitter := int64(b.scenario.LatencyJitter); jitter > 0 {
+		outcome.Delay += time.Duration(b.random.Int63n(jitter + 1))
+	}
+
+	for i, rule := range b.scenario.Rules {
+		if !rule.matches(group, song) || (rule.Times > 0 && b.used[i] >= rule.Times) {
+			continue
+		}
+		b.used[i]++
+		outcome.Delay += time.Duration(rule.Latency)
+		outcome.Status = rule.Status
+		outcome.Body = rule.Body
+		outcome.RetryAfter = rule.RetryAfter
+		return outcome
+	}
+
+	if b.scenario.ErrorRate > 0 && b.random.Float64() < b.scenario.ErrorRate {
+		outcome.Status = b.scenario.ErrorStatus
+		if outcome.Status == 0 {
+			outcome.Status = 500
+		}
+	}
+	return outcome
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"music-library/models"
+	"runtime"
+	"runtime/debug"
+)
+
+// version — версия сборки, задается при сборке: go build -ldflags "-X main.version=1.2.3" ./cmd
+var version = "dev"
+
+// buildInfo возвращает сведения о сборке. Коммит берется из данных, которые Go записывает
+// в бинарный файл при сборке из git-репозитория.
+func buildInfo() models.BuildInfo {
+	info := models.BuildInfo{Version: version, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if modified && info.Revision != "" {
+			info.Revision += "-dirty"
+		}
+	}
+	return info
+}
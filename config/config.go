@@ -1,19 +1,132 @@
 package config
 
 import (
-	"github.com/joho/godotenv"
-	"log"
+	"music-library/models"
+	"time"
 )
 
-// LoadEnv загружает переменные окружения из .env файла в текущий процесс.
-// Используется для конфигурирования приложения без необходимости жестко прописывать параметры в коде.
+// Config — конфигурация приложения.
 //
-// Если файл .env отсутствует или не может быть загружен, программа завершает работу с фатальной ошибкой.
-func LoadEnv() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("ERROR: Failed to load .env file. Ensure the file exists and is readable.")
-	}
+// Значения собираются из нескольких источников, каждый следующий переопределяет предыдущий:
+// значения по умолчанию (Default), файл .env, файл конфигурации YAML или TOML,
+// переменные окружения и флаги командной строки (см. Load).
+//
+// Теги полей:
+//   - yaml — ключ в файле конфигурации (вложенные секции разделяются точкой, например http.addr);
+//   - env — переменная окружения;
+//   - flag — флаг командной строки;
+//   - usage — описание для справки по флагам;
+//   - secret — значение скрывается в выводе команды config print.
+type Config struct {
+	HTTP       HTTPConfig       `yaml:"http"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Enrichment EnrichmentConfig `yaml:"enrichment"`
+	Pagination PaginationConfig `yaml:"pagination"`
+	Search     SearchConfig     `yaml:"search"`
+	Fuzzy      FuzzyConfig      `yaml:"fuzzy"`
+	Trash      TrashConfig      `yaml:"trash"`
+}
+
+// HTTPConfig содержит настройки HTTP-сервера API.
+type HTTPConfig struct {
+	Addr string `yaml:"addr" env:"HTTP_ADDR" flag:"http-addr" usage:"адрес HTTP-сервера API"`
 
-	log.Println("INFO: .env file successfully loaded.")
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" flag:"http-read-timeout" usage:"максимальное время чтения запроса вместе с телом"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" flag:"http-write-timeout" usage:"максимальное время обработки запроса и записи ответа"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" flag:"http-idle-timeout" usage:"время ожидания следующего запроса в keep-alive соединении"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" flag:"http-shutdown-timeout" usage:"время на завершение обрабатываемых запросов при остановке"`
+}
+
+// DatabaseConfig содержит настройки подключения к базе данных.
+type DatabaseConfig struct {
+	Driver          string        `yaml:"driver" env:"DB_DRIVER" flag:"db-driver" usage:"драйвер базы данных: postgres или sqlite"`
+	URL             string        `yaml:"url" env:"DATABASE_URL" flag:"database-url" usage:"строка подключения к PostgreSQL" secret:"true"`
+	SQLitePath      string        `yaml:"sqlite_path" env:"SQLITE_PATH" flag:"sqlite-path" usage:"путь к файлу SQLite (:memory: — база в памяти)"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" flag:"db-max-open-conns" usage:"максимум открытых соединений"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" flag:"db-max-idle-conns" usage:"максимум неактивных соединений"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" flag:"db-conn-max-lifetime" usage:"время жизни соединения (0 — без ограничения; для SQLite не применяется)"`
+}
+
+// EnrichmentConfig содержит настройки источников информации о песнях.
+type EnrichmentConfig struct {
+	File               string              `yaml:"file" env:"ENRICHMENT_FILE" flag:"enrichment-file" usage:"файл с информацией о песнях (JSON или JSON Lines)"`
+	FileReloadInterval time.Duration       `yaml:"file_reload_interval" env:"ENRICHMENT_FILE_RELOAD_INTERVAL" flag:"enrichment-file-reload-interval" usage:"интервал проверки изменений файла"`
+	API                EnrichmentAPIConfig `yaml:"api"`
+}
+
+// EnrichmentAPIConfig содержит настройки клиента внешнего API с информацией о песнях.
+type EnrichmentAPIConfig struct {
+	BaseURL string            `yaml:"url" env:"ENRICHMENT_API_URL" flag:"enrichment-api-url" usage:"базовый адрес внешнего API"`
+	Timeout time.Duration     `yaml:"timeout" env:"ENRICHMENT_API_TIMEOUT" flag:"enrichment-api-timeout" usage:"таймаут одного запроса"`
+	Headers map[string]string `yaml:"headers" env:"ENRICHMENT_API_HEADERS" flag:"enrichment-api-headers" usage:"заголовки запросов в формате \"Name: value; Other: value\"" secret:"true"`
+
+	MaxAttempts int           `yaml:"max_attempts" env:"ENRICHMENT_API_MAX_ATTEMPTS" flag:"enrichment-api-max-attempts" usage:"максимальное количество попыток запроса"`
+	BackoffBase time.Duration `yaml:"backoff_base" env:"ENRICHMENT_API_BACKOFF_BASE" flag:"enrichment-api-backoff-base" usage:"начальная задержка между попытками"`
+	BackoffMax  time.Duration `yaml:"backoff_max" env:"ENRICHMENT_API_BACKOFF_MAX" flag:"enrichment-api-backoff-max" usage:"максимальная задержка между попытками"`
+
+	BreakerThreshold int           `yaml:"breaker_threshold" env:"ENRICHMENT_API_BREAKER_THRESHOLD" flag:"enrichment-api-breaker-threshold" usage:"количество неудачных вызовов подряд до размыкания цепи"`
+	BreakerTimeout   time.Duration `yaml:"breaker_timeout" env:"ENRICHMENT_API_BREAKER_TIMEOUT" flag:"enrichment-api-breaker-timeout" usage:"время, на которое размыкается цепь"`
+}
+
+// PaginationConfig содержит ограничения размера страницы в списках.
+type PaginationConfig struct {
+	DefaultLimit int `yaml:"default_limit" env:"PAGINATION_DEFAULT_LIMIT" flag:"pagination-default-limit" usage:"размер страницы по умолчанию"`
+	MaxLimit     int `yaml:"max_limit" env:"PAGINATION_MAX_LIMIT" flag:"pagination-max-limit" usage:"максимальный размер страницы"`
+}
+
+// SearchConfig содержит настройки полнотекстового поиска.
+type SearchConfig struct {
+	DefaultLanguage string `yaml:"default_language" env:"SEARCH_DEFAULT_LANGUAGE" flag:"search-default-language" usage:"конфигурация поиска по умолчанию"`
+}
+
+// FuzzyConfig содержит пороги нечеткого поиска песен в GET /info.
+type FuzzyConfig struct {
+	MatchThreshold   float64 `yaml:"match_threshold" env:"INFO_FUZZY_MATCH_THRESHOLD" flag:"fuzzy-match-threshold" usage:"сходство, начиная с которого песня считается найденной"`
+	SuggestThreshold float64 `yaml:"suggest_threshold" env:"INFO_FUZZY_SUGGEST_THRESHOLD" flag:"fuzzy-suggest-threshold" usage:"сходство, начиная с которого песня предлагается как подсказка"`
+	Suggestions      int     `yaml:"suggestions" env:"INFO_FUZZY_SUGGESTIONS" flag:"fuzzy-suggestions" usage:"максимальное количество подсказок"`
+}
+
+// TrashConfig содержит настройки очистки корзины.
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention" env:"TRASH_RETENTION" flag:"trash-retention" usage:"срок хранения песен в корзине"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL" flag:"trash-purge-interval" usage:"интервал между запусками очистки корзины"`
+}
+
+// Default возвращает конфигурацию по умолчанию.
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Addr:            ":8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    60 * time.Second, // GET /info может ждать внешний API с повторными попытками
+			IdleTimeout:     2 * time.Minute,
+			ShutdownTimeout: 20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:       "postgres",
+			SQLitePath:   "music-library.db",
+			MaxOpenConns: 10,
+			MaxIdleConns: 5,
+		},
+		Enrichment: EnrichmentConfig{
+			File:               "song_enrichment.json",
+			FileReloadInterval: 5 * time.Second,
+			API: EnrichmentAPIConfig{
+				BaseURL: "http://localhost:8081",
+				Timeout: 5 * time.Second,
+				Headers: map[string]string{},
+
+				MaxAttempts: 3,
+				BackoffBase: 200 * time.Millisecond,
+				BackoffMax:  2 * time.Second,
+
+				BreakerThreshold: 5,
+				BreakerTimeout:   30 * time.Second,
+			},
+		},
+		Pagination: PaginationConfig{DefaultLimit: 10, MaxLimit: 100},
+		Search:     SearchConfig{DefaultLanguage: models.DefaultSearchLanguage},
+		Fuzzy:      FuzzyConfig{MatchThreshold: 0.7, SuggestThreshold: 0.3, Suggestions: 5},
+		Trash:      TrashConfig{Retention: 30 * 24 * time.Hour, PurgeInterval: time.Hour},
+	}
 }
@@ -0,0 +1,356 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv — переменная окружения с путем к файлу конфигурации (флаг -config имеет приоритет).
+const FileEnv = "CONFIG_FILE"
+
+// dotEnvPath — файл с переменными окружения для локальной разработки.
+const dotEnvPath = ".env"
+
+// durationType — тип time.Duration, который разбирается из строк вида "5s".
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field — одно значение конфигурации, доступное для изменения из источников.
+type field struct {
+	Key    string        // Путь в файле конфигурации, например http.addr
+	Env    string        // Переменная окружения
+	Flag   string        // Флаг командной строки
+	Usage  string        // Описание флага
+	Secret bool          // Значение скрывается при выводе
+	Value  reflect.Value // Поле структуры Config
+}
+
+// Load собирает конфигурацию из всех источников и проверяет ее.
+//
+// Порядок применения: значения по умолчанию, файл .env (если он есть), файл конфигурации
+// (флаг -config или CONFIG_FILE; формат определяется расширением: .yaml, .yml или .toml),
+// переменные окружения процесса и флаги из args. Значения из .env не попадают в окружение
+// процесса и не переопределяют ни файл конфигурации, ни переменные окружения.
+//
+// Принимает:
+//   - name string: имя программы для справки по флагам.
+//   - args []string: аргументы командной строки без имени программы.
+//
+// Возвращает:
+//   - *Config: собранная конфигурация (заполнена, даже если в ней есть ошибки).
+//   - []string: аргументы после флагов (команда и ее параметры).
+//   - error: все ошибки разбора и проверки, объединенные через errors.Join;
+//     flag.ErrHelp, если запрошена справка.
+func Load(name string, args []string) (*Config, []string, error) {
+	cfg := Default()
+	fields := cfg.fields()
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() { PrintUsage(flags.Output(), name) }
+	configFile := flags.String("config", "", "файл конфигурации YAML или TOML (по умолчанию $"+FileEnv+")")
+	flagValues := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		if f.Flag != "" {
+			flagValues[f.Flag] = flags.String(f.Flag, "", f.Usage)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return &cfg, nil, err
+	}
+
+	var errs []error
+	dotEnv, err := loadDotEnv(dotEnvPath)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// Файл .env
+	errs = append(errs, applyEnv(fields, dotEnv.lookup, dotEnvPath)...)
+
+	// Файл конфигурации
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv(FileEnv, os.LookupEnv, dotEnv.lookup)
+	}
+	if path != "" {
+		errs = append(errs, cfg.applyFile(path)...)
+	}
+
+	// Переменные окружения процесса
+	errs = append(errs, applyEnv(fields, os.LookupEnv, "env")...)
+
+	// Флаги командной строки (только явно указанные)
+	byFlag := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byFlag[f.Flag] = f
+	}
+	flags.Visit(func(fl *flag.Flag) {
+		if f, ok := byFlag[fl.Name]; ok && fl.Name != "" {
+			if err := f.set(*flagValues[fl.Name]); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", fl.Name, err))
+			}
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return &cfg, flags.Args(), errors.Join(errs...)
+}
+
+// envFile — переменные из файла .env.
+type envFile map[string]string
+
+// lookup возвращает значение переменной из файла, как os.LookupEnv.
+func (e envFile) lookup(name string) (string, bool) {
+	value, ok := e[name]
+	return value, ok
+}
+
+// loadDotEnv читает переменные из файла path, если он существует. Окружение процесса не меняется.
+func loadDotEnv(path string) (envFile, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	log.Printf("INFO: %s file successfully loaded.", path)
+	return values, nil
+}
+
+// lookupEnv ищет переменную в источниках по порядку и возвращает первое непустое значение.
+func lookupEnv(name string, sources ...func(string) (string, bool)) (string, bool) {
+	for _, lookup := range sources {
+		if value, ok := lookup(name); ok && value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// applyEnv применяет значения переменных окружения из источника lookup.
+// Пустые значения пропускаются; source указывается в тексте ошибок.
+func applyEnv(fields []field, lookup func(string) (string, bool), source string) []error {
+	var errs []error
+	for _, f := range fields {
+		if f.Env == "" {
+			continue
+		}
+		if raw, ok := lookupEnv(f.Env, lookup); ok {
+			if err := f.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", source, f.Env, err))
+			}
+		}
+	}
+	return errs
+}
+
+// applyFile применяет значения из файла конфигурации. Неизвестные ключи считаются ошибкой.
+func (c *Config) applyFile(path string) []error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []error{fmt.Errorf("config file: %w", err)}
+	}
+
+	values := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return []error{fmt.Errorf("config file %s: unsupported format %q, expected .yaml, .yml or .toml", path, ext)}
+	}
+	if err != nil {
+		return []error{fmt.Errorf("config file %s: %w", path, err)}
+	}
+
+	byKey := make(map[string]field)
+	for _, f := range c.fields() {
+		byKey[f.Key] = f
+	}
+	return applyFileValues(path, byKey, "", values)
+}
+
+// applyFileValues рекурсивно применяет значения секции файла конфигурации с префиксом prefix.
+func applyFileValues(path string, byKey map[string]field, prefix string, values map[string]interface{}) []error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // Ошибки выводятся в стабильном порядке
+
+	var errs []error
+	for _, key := range keys {
+		fullKey := prefix + key
+		value := values[key]
+		if f, ok := byKey[fullKey]; ok {
+			if err := f.setFileValue(value); err != nil {
+				errs = append(errs, fmt.Errorf("config file %s: %s: %w", path, fullKey, err))
+			}
+			continue
+		}
+		if section, ok := value.(map[string]interface{}); ok {
+			errs = append(errs, applyFileValues(path, byKey, fullKey+".", section)...)
+			continue
+		}
+		errs = append(errs, fmt.Errorf("config file %s: unknown key %s", path, fullKey))
+	}
+	return errs
+}
+
+// fields возвращает все значения конфигурации в порядке объявления.
+func (c *Config) fields() []field {
+	var fields []field
+	collectFields(reflect.ValueOf(c).Elem(), "", &fields)
+	return fields
+}
+
+// collectFields добавляет в fields поля структуры v, спускаясь во вложенные секции.
+func collectFields(v reflect.Value, prefix string, fields *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := sf.Tag.Get("yaml")
+		if key == "" || key == "-" {
+			continue
+		}
+		key = prefix + key
+		if sf.Type.Kind() == reflect.Struct {
+			collectFields(v.Field(i), key+".", fields)
+			continue
+		}
+		*fields = append(*fields, field{
+			Key:    key,
+			Env:    sf.Tag.Get("env"),
+			Flag:   sf.Tag.Get("flag"),
+			Usage:  sf.Tag.Get("usage"),
+			Secret: sf.Tag.Get("secret") == "true",
+			Value:  v.Field(i),
+		})
+	}
+}
+
+// set разбирает строковое значение (из окружения или флага) в тип поля.
+func (f field) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch f.Value.Kind() {
+	case reflect.String:
+		f.Value.SetString(raw)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.Value.SetBool(value)
+	case reflect.Int, reflect.Int64:
+		if f.Value.Type() == durationType {
+			value, err := time.ParseDuration(raw)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", raw)
+			}
+			f.Value.SetInt(int64(value))
+			return nil
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.Value.SetInt(int64(value))
+	case reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		f.Value.SetFloat(value)
+	case reflect.Map:
+		f.Value.Set(reflect.ValueOf(parseHeaders(raw)))
+	default:
+		return fmt.Errorf("unsupported type %s", f.Value.Type())
+	}
+	return nil
+}
+
+// setFileValue применяет значение из файла конфигурации. Словари (заголовки) можно задать
+// как секцией, так и строкой; остальные значения разбираются так же, как переменные окружения.
+func (f field) setFileValue(value interface{}) error {
+	if section, ok := value.(map[string]interface{}); ok {
+		if f.Value.Kind() != reflect.Map {
+			return errors.New("must be a single value, not a section")
+		}
+		headers := make(map[string]string, len(section))
+		for name, headerValue := range section {
+			headers[name] = fmt.Sprint(headerValue)
+		}
+		f.Value.Set(reflect.ValueOf(headers))
+		return nil
+	}
+	return f.set(fmt.Sprint(value))
+}
+
+// display возвращает значение поля в виде строки для справки и вывода конфигурации.
+func (f field) display() string {
+	switch value := f.Value.Interface().(type) {
+	case time.Duration:
+		return value.String()
+	case map[string]string:
+		return formatHeaders(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// parseHeaders разбирает список заголовков вида "Name: value; Other: value".
+func parseHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(raw, ";") {
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			continue
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			headers[name] = strings.TrimSpace(value)
+		}
+	}
+	return headers
+}
+
+// formatHeaders записывает заголовки в формате parseHeaders в порядке имен.
+func formatHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+": "+headers[name])
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// PrintUsage выводит справку по флагам и переменным окружения в w.
+func PrintUsage(w io.Writer, name string) {
+	cfg := Default()
+	fmt.Fprintf(w, "Usage: %s [flags] [migrate up|down|to N|status | config print]\n\nFlags:\n", name)
+	fmt.Fprintf(w, "  -config string\n\tфайл конфигурации YAML или TOML (env %s)\n", FileEnv)
+	for _, f := range cfg.fields() {
+		fmt.Fprintf(w, "  -%s\n\t%s (env %s, file %s, по умолчанию %q)\n", f.Flag, f.Usage, f.Env, f.Key, f.display())
+	}
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chdirTemp переходит во временный каталог на время теста, чтобы .env из репозитория не влиял на результат.
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		dotEnv string
+		file   string
+		env    map[string]string
+		args   []string
+		want   string
+	}{
+		{name: "default", want: ":8080"},
+		{name: "dotenv", dotEnv: "HTTP_ADDR=:1111\n", want: ":1111"},
+		{name: "file over dotenv", dotEnv: "HTTP_ADDR=:1111\n", file: "http:\n  addr: \":2222\"\n", want: ":2222"},
+		{name: "env over file", file: "http:\n  addr: \":2222\"\n", env: map[string]string{"HTTP_ADDR": ":3333"}, want: ":3333"},
+		{name: "flag over env", env: map[string]string{"HTTP_ADDR": ":3333"}, args: []string{"-http-addr", ":4444"}, want: ":4444"},
+		{name: "empty env is ignored", file: "http:\n  addr: \":2222\"\n", env: map[string]string{"HTTP_ADDR": ""}, want: ":2222"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := chdirTemp(t)
+			t.Setenv("HTTP_ADDR", "")
+			t.Setenv(FileEnv, "")
+			t.Setenv("DATABASE_URL", "postgres://localhost/db")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			if tt.dotEnv != "" {
+				writeFile(t, filepath.Join(dir, ".env"), tt.dotEnv)
+			}
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(dir, "config.yaml")
+				writeFile(t, path, tt.file)
+				args = append([]string{"-config", path}, args...)
+			}
+
+			cfg, _, err := Load("test", args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.HTTP.Addr != tt.want {
+				t.Errorf("HTTP.Addr = %q, want %q", cfg.HTTP.Addr, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDotEnvDoesNotChangeEnvironment(t *testing.T) {
+	dir := chdirTemp(t)
+	t.Setenv("DATABASE_URL", "postgres://localhost/db")
+	t.Setenv("TRASH_RETENTION", "") // Восстанавливает исходное значение после теста
+	os.Unsetenv("TRASH_RETENTION")
+	writeFile(t, filepath.Join(dir, ".env"), "TRASH_RETENTION=1h\n")
+
+	cfg, _, err := Load("test", nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Trash.Retention != time.Hour {
+		t.Errorf("Trash.Retention = %s, want 1h", cfg.Trash.Retention)
+	}
+	if _, ok := os.LookupEnv("TRASH_RETENTION"); ok {
+		t.Error("TRASH_RETENTION was exported to the process environment")
+	}
+}
+
+func TestLoadFileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{name: "yaml", file: "c.yaml", content: "pagination:\n  max_limit: 50\nenrichment:\n  api:\n    headers:\n      X-Key: abc\n"},
+		{name: "toml", file: "c.toml", content: "[pagination]\nmax_limit = 50\n[enrichment.api.headers]\nX-Key = \"abc\"\n"},
+		{name: "unknown key", file: "c.yaml", content: "pagination:\n  max_limit: 50\nbogus: 1\n", wantErr: "unknown key bogus"},
+		{name: "unsupported format", file: "c.json", content: "{}", wantErr: "unsupported format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := chdirTemp(t)
+			t.Setenv("DATABASE_URL", "postgres://localhost/db")
+			t.Setenv("PAGINATION_MAX_LIMIT", "")
+			path := filepath.Join(dir, tt.file)
+			writeFile(t, path, tt.content)
+
+			cfg, _, err := Load("test", []string{"-config", path})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Pagination.MaxLimit != 50 {
+				t.Errorf("Pagination.MaxLimit = %d, want 50", cfg.Pagination.MaxLimit)
+			}
+			if cfg.Enrichment.API.Headers["X-Key"] != "abc" {
+				t.Errorf("Headers = %v, want X-Key: abc", cfg.Enrichment.API.Headers)
+			}
+		})
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("DATABASE_URL", "")
+	t.Setenv("PAGINATION_DEFAULT_LIMIT", "500")
+	t.Setenv("INFO_FUZZY_MATCH_THRESHOLD", "2")
+
+	_, _, err := Load("test", []string{"-trash-retention", "xx", "-http-addr", "bad"})
+	if err == nil {
+		t.Fatal("Load() error = nil")
+	}
+	for _, want := range []string{
+		"flag -trash-retention",
+		"http.addr",
+		"database.url",
+		"pagination.default_limit",
+		"fuzzy.match_threshold",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestLoadHelpAndArgs(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("DATABASE_URL", "postgres://localhost/db")
+
+	if _, _, err := Load("test", []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Load(-h) error = %v, want flag.ErrHelp", err)
+	}
+
+	_, args, err := Load("test", []string{"migrate", "to", "3"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if strings.Join(args, " ") != "migrate to 3" {
+		t.Errorf("args = %v, want [migrate to 3]", args)
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want map[string]string
+	}{
+		{raw: "", want: map[string]string{}},
+		{raw: "Authorization: Bearer x", want: map[string]string{"Authorization": "Bearer x"}},
+		{raw: " A: 1 ; B:2;broken; : empty", want: map[string]string{"A": "1", "B": "2"}},
+		{raw: "URL: http://host:8080", want: map[string]string{"URL": "http://host:8080"}},
+	}
+
+	for _, tt := range tests {
+		got := parseHeaders(tt.raw)
+		if len(got) != len(tt.want) {
+			t.Errorf("parseHeaders(%q) = %v, want %v", tt.raw, got, tt.want)
+			continue
+		}
+		for name, value := range tt.want {
+			if got[name] != value {
+				t.Errorf("parseHeaders(%q)[%s] = %q, want %q", tt.raw, name, got[name], value)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted заменяет значения секретных параметров в выводе конфигурации.
+const redacted = "[REDACTED]"
+
+// Redacted возвращает копию конфигурации, в которой значения секретных параметров скрыты.
+// В адресах с паролем (например, строке подключения к PostgreSQL) скрывается только пароль.
+func (c Config) Redacted() Config {
+	copied := c
+	copied.Enrichment.API.Headers = make(map[string]string, len(c.Enrichment.API.Headers))
+	for name, value := range c.Enrichment.API.Headers {
+		copied.Enrichment.API.Headers[name] = value
+	}
+
+	for _, f := range copied.fields() {
+		if !f.Secret {
+			continue
+		}
+		switch f.Value.Kind() {
+		case reflect.String:
+			if value := f.Value.String(); value != "" {
+				f.Value.SetString(redactString(value))
+			}
+		case reflect.Map:
+			for _, name := range f.Value.MapKeys() {
+				f.Value.SetMapIndex(name, reflect.ValueOf(redacted))
+			}
+		}
+	}
+	return copied
+}
+
+// redactString скрывает секретное значение: пароль в URL или значение целиком.
+func redactString(value string) string {
+	if parsed, err := url.Parse(value); err == nil && parsed.User != nil {
+		if _, hasPassword := parsed.User.Password(); hasPassword {
+			return parsed.Redacted()
+		}
+	}
+	return redacted
+}
+
+// Print выводит действующую конфигурацию в формате YAML со скрытыми секретами.
+// Вывод можно использовать как файл конфигурации (после замены скрытых значений).
+func (c Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
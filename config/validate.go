@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"music-library/models"
+)
+
+// Validate проверяет конфигурацию и возвращает все найденные ошибки сразу (errors.Join).
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	_, _, err := net.SplitHostPort(c.HTTP.Addr)
+	check(err == nil, "http.addr", "must be in host:port form, got %q", c.HTTP.Addr)
+	check(c.HTTP.ReadTimeout > 0, "http.read_timeout", "must be positive")
+	check(c.HTTP.WriteTimeout > 0, "http.write_timeout", "must be positive")
+	check(c.HTTP.IdleTimeout > 0, "http.idle_timeout", "must be positive")
+	check(c.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout", "must be positive")
+
+	db := c.Database
+	// Значения совпадают с database.DriverPostgres и database.DriverSQLite
+	check(db.Driver == "postgres" || db.Driver == "sqlite", "database.driver", "must be postgres or sqlite, got %q", db.Driver)
+	check(db.Driver != "postgres" || db.URL != "", "database.url", "is required for the postgres driver")
+	check(db.Driver != "sqlite" || db.SQLitePath != "", "database.sqlite_path", "is required for the sqlite driver")
+	check(db.MaxOpenConns > 0, "database.max_open_conns", "must be positive")
+	check(db.MaxIdleConns >= 0, "database.max_idle_conns", "must not be negative")
+	check(db.MaxIdleConns <= db.MaxOpenConns, "database.max_idle_conns", "must not exceed database.max_open_conns (%d)", db.MaxOpenConns)
+	check(db.ConnMaxLifetime >= 0, "database.conn_max_lifetime", "must not be negative")
+
+	enrichment := c.Enrichment
+	check(enrichment.FileReloadInterval > 0, "enrichment.file_reload_interval", "must be positive")
+	api := enrichment.API
+	apiURL, err := url.Parse(api.BaseURL)
+	check(err == nil && (apiURL.Scheme == "http" || apiURL.Scheme == "https") && apiURL.Host != "",
+		"enrichment.api.url", "must be an absolute http(s) URL, got %q", api.BaseURL)
+	check(api.Timeout > 0, "enrichment.api.timeout", "must be positive")
+	check(api.MaxAttempts > 0, "enrichment.api.max_attempts", "must be positive")
+	check(api.BackoffBase > 0, "enrichment.api.backoff_base", "must be positive")
+	check(api.BackoffMax >= api.BackoffBase, "enrichment.api.backoff_max", "must not be less than enrichment.api.backoff_base (%s)", api.BackoffBase)
+	check(api.BreakerThreshold > 0, "enrichment.api.breaker_threshold", "must be positive")
+	check(api.BreakerTimeout > 0, "enrichment.api.breaker_timeout", "must be positive")
+
+	check(c.Pagination.DefaultLimit > 0, "pagination.default_limit", "must be positive")
+	check(c.Pagination.MaxLimit > 0, "pagination.max_limit", "must be positive")
+	check(c.Pagination.DefaultLimit <= c.Pagination.MaxLimit, "pagination.default_limit", "must not exceed pagination.max_limit (%d)", c.Pagination.MaxLimit)
+
+	check(models.IsSearchLanguage(c.Search.DefaultLanguage), "search.default_language", "unsupported language %q", c.Search.DefaultLanguage)
+
+	fuzzy := c.Fuzzy
+	check(fuzzy.MatchThreshold >= 0 && fuzzy.MatchThreshold <= 1, "fuzzy.match_threshold", "must be between 0 and 1")
+	check(fuzzy.SuggestThreshold >= 0 && fuzzy.SuggestThreshold <= 1, "fuzzy.suggest_threshold", "must be between 0 and 1")
+	check(fuzzy.SuggestThreshold <= fuzzy.MatchThreshold, "fuzzy.suggest_threshold", "must not exceed fuzzy.match_threshold (%g)", fuzzy.MatchThreshold)
+	check(fuzzy.Suggestions > 0, "fuzzy.suggestions", "must be positive")
+
+	check(c.Trash.Retention > 0, "trash.retention", "must be positive")
+	check(c.Trash.PurgeInterval > 0, "trash.purge_interval", "must be positive")
+
+	return errors.Join(errs...)
+}
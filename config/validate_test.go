@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr []string // Ключи, которые должны быть в ошибке; пусто — конфигурация корректна
+	}{
+		{name: "defaults with url", modify: func(c *Config) {}},
+		{name: "sqlite without url", modify: func(c *Config) { c.Database.Driver, c.Database.URL = "sqlite", "" }},
+		{name: "postgres without url", modify: func(c *Config) { c.Database.URL = "" }, wantErr: []string{"database.url"}},
+		{name: "unknown driver", modify: func(c *Config) { c.Database.Driver = "mysql" }, wantErr: []string{"database.driver"}},
+		{name: "bad addr", modify: func(c *Config) { c.HTTP.Addr = "8080" }, wantErr: []string{"http.addr"}},
+		{name: "idle over open", modify: func(c *Config) { c.Database.MaxIdleConns = 20 }, wantErr: []string{"database.max_idle_conns"}},
+		{name: "relative api url", modify: func(c *Config) { c.Enrichment.API.BaseURL = "localhost:8081" }, wantErr: []string{"enrichment.api.url"}},
+		{name: "backoff order", modify: func(c *Config) { c.Enrichment.API.BackoffMax = time.Millisecond }, wantErr: []string{"enrichment.api.backoff_max"}},
+		{name: "unknown language", modify: func(c *Config) { c.Search.DefaultLanguage = "klingon" }, wantErr: []string{"search.default_language"}},
+		{name: "suggest over match", modify: func(c *Config) { c.Fuzzy.SuggestThreshold = 0.9 }, wantErr: []string{"fuzzy.suggest_threshold"}},
+		{
+			name: "several errors",
+			modify: func(c *Config) {
+				c.Pagination.DefaultLimit = 0
+				c.Trash.Retention = 0
+				c.HTTP.ShutdownTimeout = -time.Second
+			},
+			wantErr: []string{"pagination.default_limit", "trash.retention", "http.shutdown_timeout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Database.URL = "postgres://localhost/db"
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() error = nil, want %v", tt.wantErr)
+			}
+			for _, key := range tt.wantErr {
+				if !strings.Contains(err.Error(), key) {
+					t.Errorf("Validate() error %q does not mention %s", err, key)
+				}
+			}
+		})
+	}
+}
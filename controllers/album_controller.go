@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"log"
+	"music-library/config"
+	"music-library/models"
+	"music-library/repository"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AlbumHandler обрабатывает запросы к альбомам и их трек-листам.
+type AlbumHandler struct {
+	Albums     *repository.AlbumRepository // Репозиторий альбомов
+	Pagination config.PaginationConfig     // Ограничения размера страницы
+}
+
+// NewAlbumHandler создает обработчик с указанным репозиторием и ограничениями размера страницы
+func NewAlbumHandler(albums *repository.AlbumRepository, pagination config.PaginationConfig) *AlbumHandler {
+	return &AlbumHandler{Albums: albums, Pagination: pagination}
+}
+
+// GetAlbums возвращает список альбомов с пагинацией и фильтром по исполнителю
+func (h *AlbumHandler) GetAlbums(c *gin.Context) {
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+	artistID, paramErr := parseOptionalIDQuery(c, "artist_id")
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	albums, total, err := h.Albums.GetAlbums(c.Request.Context(), artistID, pager.Page, pager.Limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AlbumListResponse{
+		Albums: albums,
+		Total:  total,
+		Page:   pager.Page,
+		Limit:  pager.Limit,
+	})
+}
+
+// GetAlbum возвращает альбом по ID
+func (h *AlbumHandler) GetAlbum(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	album, err := h.Albums.GetAlbumByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve album with ID %d: %v", id, err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// CreateAlbum создает новый альбом
+func (h *AlbumHandler) CreateAlbum(c *gin.Context) {
+	var request models.AlbumRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid album data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	album := models.Album{}
+	if err := applyAlbumRequest(&album, request); err != nil {
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	created, err := h.Albums.CreateAlbum(c.Request.Context(), &album)
+	if errors.Is(err, repository.ErrAlbumArtistNotFound) {
+		c.String(http.StatusUnprocessableEntity, "artist not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/albums/%d", created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateAlbum обновляет альбом
+func (h *AlbumHandler) UpdateAlbum(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	album, err := h.Albums.GetAlbumByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	var request models.AlbumRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid album data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+	if err := applyAlbumRequest(album, request); err != nil {
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	updated, err := h.Albums.UpdateAlbum(c.Request.Context(), album)
+	if errors.Is(err, repository.ErrAlbumArtistNotFound) {
+		c.String(http.StatusUnprocessableEntity, "artist not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteAlbum удаляет альбом, оставляя его песни в библиотеке
+func (h *AlbumHandler) DeleteAlbum(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Albums.DeleteAlbum(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetAlbumTracks возвращает трек-лист альбома в порядке дисков и номеров треков
+func (h *AlbumHandler) GetAlbumTracks(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	album, tracks, err := h.Albums.GetAlbumTracks(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AlbumTracksResponse{Album: *album, Tracks: tracks})
+}
+
+// AttachAlbumTrack помещает песню в альбом на указанную позицию
+func (h *AlbumHandler) AttachAlbumTrack(c *gin.Context) {
+	albumID, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+	songID, paramErr := parseNamedIDParam(c, "song_id")
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	var request models.AlbumTrackRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid track data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+	if request.DiscNumber == 0 {
+		request.DiscNumber = 1
+	}
+
+	song, err := h.Albums.AttachSong(c.Request.Context(), albumID, songID, request.DiscNumber, request.TrackNumber)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case errors.Is(err, repository.ErrTrackOutOfRange):
+		c.String(http.StatusUnprocessableEntity, "track number exceeds album total tracks")
+	case errors.Is(err, repository.ErrTrackPositionTaken):
+		c.String(http.StatusConflict, "track position is already taken")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.JSON(http.StatusOK, song)
+	}
+}
+
+// DetachAlbumTrack убирает песню из альбома
+func (h *AlbumHandler) DetachAlbumTrack(c *gin.Context) {
+	albumID, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+	songID, paramErr := parseNamedIDParam(c, "song_id")
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Albums.DetachSong(c.Request.Context(), albumID, songID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// applyAlbumRequest переносит поля запроса в модель альбома
+func applyAlbumRequest(album *models.Album, request models.AlbumRequest) error {
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return errors.New("title must not be blank")
+	}
+
+	album.ReleaseDate = nil
+	if request.ReleaseDate != "" {
+		releaseDate, err := time.Parse("2006-01-02", request.ReleaseDate)
+		if err != nil {
+			return err
+		}
+		album.ReleaseDate = &releaseDate
+	}
+
+	album.Title = title
+	album.ArtistID = request.ArtistID
+	album.Artist = nil
+	album.CoverLink = strings.TrimSpace(request.CoverLink)
+	album.TotalTracks = request.TotalTracks
+	return nil
+}
@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"log"
+	"music-library/config"
+	"music-library/models"
+	"music-library/repository"
+	"net/http"
+	"strings"
+)
+
+// ArtistHandler обрабатывает запросы к исполнителям.
+type ArtistHandler struct {
+	Artists    *repository.ArtistRepository // Репозиторий исполнителей
+	Pagination config.PaginationConfig      // Ограничения размера страницы
+}
+
+// NewArtistHandler создает обработчик с указанным репозиторием и ограничениями размера страницы
+func NewArtistHandler(artists *repository.ArtistRepository, pagination config.PaginationConfig) *ArtistHandler {
+	return &ArtistHandler{Artists: artists, Pagination: pagination}
+}
+
+// GetArtists возвращает список исполнителей с пагинацией и фильтром по имени
+func (h *ArtistHandler) GetArtists(c *gin.Context) {
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	artists, total, err := h.Artists.GetArtists(c.Request.Context(), strings.TrimSpace(c.Query("name")), pager.Page, pager.Limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ArtistListResponse{
+		Artists: artists,
+		Total:   total,
+		Page:    pager.Page,
+		Limit:   pager.Limit,
+	})
+}
+
+// GetArtist возвращает исполнителя по ID
+func (h *ArtistHandler) GetArtist(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	artist, err := h.Artists.GetArtistByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve artist with ID %d: %v", id, err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// CreateArtist создает нового исполнителя
+func (h *ArtistHandler) CreateArtist(c *gin.Context) {
+	var request models.ArtistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid artist data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		c.String(http.StatusBadRequest, "invalid input: name must not be blank")
+		return
+	}
+
+	artist := models.Artist{}
+	applyArtistRequest(&artist, request)
+
+	created, err := h.Artists.CreateArtist(c.Request.Context(), &artist)
+	if errors.Is(err, repository.ErrDuplicateArtist) {
+		c.String(http.StatusConflict, "artist already exists")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/artists/%d", created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateArtist обновляет исполнителя
+func (h *ArtistHandler) UpdateArtist(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	artist, err := h.Artists.GetArtistByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	var request models.ArtistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid artist data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		c.String(http.StatusBadRequest, "invalid input: name must not be blank")
+		return
+	}
+	applyArtistRequest(artist, request)
+
+	updated, err := h.Artists.UpdateArtist(c.Request.Context(), artist)
+	if errors.Is(err, repository.ErrDuplicateArtist) {
+		c.String(http.StatusConflict, "artist with this name already exists")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteArtist удаляет исполнителя без песен и альбомов
+func (h *ArtistHandler) DeleteArtist(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Artists.DeleteArtist(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case errors.Is(err, repository.ErrArtistHasSongs):
+		c.String(http.StatusConflict, "artist has songs and cannot be deleted")
+	case errors.Is(err, repository.ErrArtistHasAlbums):
+		c.String(http.StatusConflict, "artist has albums and cannot be deleted")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetArtistSongs возвращает песни исполнителя с пагинацией
+func (h *ArtistHandler) GetArtistSongs(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	songs, total, err := h.Artists.GetArtistSongs(c.Request.Context(), id, pager.Page, pager.Limit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SongListResponse{
+		Songs: songs,
+		Total: total,
+		Page:  pager.Page,
+		Limit: pager.Limit,
+	})
+}
+
+// applyArtistRequest переносит поля запроса в модель исполнителя
+func applyArtistRequest(artist *models.Artist, request models.ArtistRequest) {
+	artist.Name = strings.TrimSpace(request.Name)
+	artist.SortName = strings.TrimSpace(request.SortName)
+	artist.Country = strings.TrimSpace(request.Country)
+	artist.FormedYear = request.FormedYear
+	artist.Aliases = make([]string, 0, len(request.Aliases))
+	for _, alias := range request.Aliases {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			artist.Aliases = append(artist.Aliases, alias)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"log"
+	"music-library/models"
+	"music-library/repository"
+	"net/http"
+	"strings"
+)
+
+// GenreHandler обрабатывает запросы к жанрам и жанрам песен.
+type GenreHandler struct {
+	Genres *repository.GenreRepository // Репозиторий жанров
+}
+
+// NewGenreHandler создает обработчик с указанным репозиторием
+func NewGenreHandler(genres *repository.GenreRepository) *GenreHandler {
+	return &GenreHandler{Genres: genres}
+}
+
+// GetGenres возвращает все жанры; иерархия передается через parent_id
+func (h *GenreHandler) GetGenres(c *gin.Context) {
+	genres, err := h.Genres.GetGenres(c.Request.Context())
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GenreListResponse{Genres: genres, Total: int64(len(genres))})
+}
+
+// GetGenre возвращает жанр по ID
+func (h *GenreHandler) GetGenre(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	genre, err := h.Genres.GetGenreByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve genre with ID %d: %v", id, err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// CreateGenre создает новый жанр
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var request models.GenreRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid genre data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		c.String(http.StatusBadRequest, "invalid input: name must not be blank")
+		return
+	}
+
+	genre := models.Genre{Name: request.Name, ParentID: request.ParentID}
+	created, err := h.Genres.CreateGenre(c.Request.Context(), &genre)
+	if err != nil {
+		respondGenreError(c, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/genres/%d", created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateGenre переименовывает жанр или перемещает его в другую ветку иерархии
+func (h *GenreHandler) UpdateGenre(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	genre, err := h.Genres.GetGenreByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	var request models.GenreRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid genre data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		c.String(http.StatusBadRequest, "invalid input: name must not be blank")
+		return
+	}
+	genre.Name, genre.ParentID = request.Name, request.ParentID
+
+	updated, err := h.Genres.UpdateGenre(c.Request.Context(), genre)
+	if err != nil {
+		respondGenreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteGenre удаляет жанр без поджанров
+func (h *GenreHandler) DeleteGenre(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Genres.DeleteGenre(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case errors.Is(err, repository.ErrGenreHasChildren):
+		c.String(http.StatusConflict, "genre has subgenres and cannot be deleted")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// SetSongGenres заменяет набор жанров песни
+func (h *GenreHandler) SetSongGenres(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	var request models.SongGenresRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid song genres data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	song, err := h.Genres.SetSongGenres(c.Request.Context(), id, request.GenreIDs)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case errors.Is(err, repository.ErrGenreNotFound):
+		c.String(http.StatusUnprocessableEntity, "genre not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.JSON(http.StatusOK, song)
+	}
+}
+
+// respondGenreError отправляет ответ для ошибок создания и обновления жанра
+func respondGenreError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrDuplicateGenre):
+		c.String(http.StatusConflict, "genre already exists")
+	case errors.Is(err, repository.ErrGenreParentNotFound):
+		c.String(http.StatusUnprocessableEntity, "parent genre not found")
+	case errors.Is(err, repository.ErrGenreCycle):
+		c.String(http.StatusUnprocessableEntity, "genre cannot be nested into itself or its subgenre")
+	default:
+		c.String(http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"music-library/database"
+	"music-library/models"
+	"music-library/providers"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout ограничивает время одной проверки зависимости
+const healthCheckTimeout = 2 * time.Second
+
+// Имена проверяемых зависимостей
+const (
+	dependencyDatabase   = "database"
+	dependencyMigrations = "migrations"
+	dependencyEnrichment = "enrichment_api"
+)
+
+// HealthHandler обрабатывает запросы проверки работоспособности сервиса.
+//
+// Сервис готов принимать запросы, если доступна база данных и схема приведена к версии,
+// известной сборке. Недоступность внешнего API не делает сервис неготовым: песни из базы
+// и локального файла продолжают отдаваться, поэтому API отмечается как degraded.
+type HealthHandler struct {
+	DB        *gorm.DB                // Соединение с базой данных
+	Migrator  *database.Migrator      // Мигратор для сравнения версии схемы с ожидаемой
+	Upstream  *providers.HTTPProvider // Клиент внешнего API с информацией о песнях
+	Build     models.BuildInfo        // Сведения о сборке
+	StartedAt time.Time               // Время запуска сервиса
+}
+
+// NewHealthHandler создает обработчик проверок с указанными зависимостями
+func NewHealthHandler(db *gorm.DB, migrator *database.Migrator, upstream *providers.HTTPProvider, build models.BuildInfo) *HealthHandler {
+	return &HealthHandler{DB: db, Migrator: migrator, Upstream: upstream, Build: build, StartedAt: time.Now()}
+}
+
+// Healthz сообщает, что процесс запущен и обрабатывает запросы. Зависимости не проверяются.
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": models.HealthOK})
+}
+
+// Readyz проверяет базу данных, версию схемы и состояние выключателя внешнего API.
+// Возвращает 503, если не прошла хотя бы одна критичная проверка.
+// Внешний API не запрашивается, чтобы частые проверки оркестратора не создавали на него нагрузку.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	checks := h.runChecks(c.Request.Context(), false)
+	status := overallStatus(checks)
+
+	code := http.StatusOK
+	if status == models.HealthDown {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, models.ReadinessResponse{Status: status, Checks: checks})
+}
+
+// Status возвращает подробное состояние сервиса: результаты и время проверки каждой зависимости
+// (внешний API запрашивается), статистику пула соединений, версию сборки и время работы.
+// Всегда отвечает 200: для решения о готовности используется /readyz.
+func (h *HealthHandler) Status(c *gin.Context) {
+	checks := h.runChecks(c.Request.Context(), true)
+
+	var pool models.DBPoolStats
+	if sqlDB, err := h.DB.DB(); err == nil {
+		stats := sqlDB.Stats()
+		pool = models.DBPoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMS:     milliseconds(stats.WaitDuration),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		}
+	}
+
+	c.JSON(http.StatusOK, models.StatusResponse{
+		Status:        overallStatus(checks),
+		Build:         h.Build,
+		StartedAt:     h.StartedAt,
+		UptimeSeconds: time.Since(h.StartedAt).Seconds(),
+		Dependencies:  checks,
+		DBPool:        pool,
+	})
+}
+
+// runChecks параллельно проверяет все зависимости. Если probeUpstream равен true,
+// доступность внешнего API проверяется запросом, иначе только по состоянию выключателя.
+func (h *HealthHandler) runChecks(ctx context.Context, probeUpstream bool) map[string]models.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	checks := map[string]func(context.Context) models.DependencyStatus{
+		dependencyDatabase:   h.checkDatabase,
+		dependencyMigrations: h.checkMigrations,
+		dependencyEnrichment: func(ctx context.Context) models.DependencyStatus {
+			return h.checkEnrichment(ctx, probeUpstream)
+		},
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]models.DependencyStatus, len(checks))
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) models.DependencyStatus) {
+			defer wg.Done()
+			started := time.Now()
+			result := check(ctx)
+			result.LatencyMS = milliseconds(time.Since(started))
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+	return results
+}
+
+// checkDatabase проверяет соединение с базой данных
+func (h *HealthHandler) checkDatabase(ctx context.Context) models.DependencyStatus {
+	result := models.DependencyStatus{Status: models.HealthOK, Critical: true}
+	sqlDB, err := h.DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		result.Status, result.Error = models.HealthDown, err.Error()
+	}
+	return result
+}
+
+// checkMigrations проверяет, что версия схемы совпадает с последней миграцией сборки
+func (h *HealthHandler) checkMigrations(ctx context.Context) models.DependencyStatus {
+	result := models.DependencyStatus{Status: models.HealthOK, Critical: true}
+	expected := h.Migrator.Latest()
+	current, err := h.Migrator.WithContext(ctx).Current()
+	if err != nil {
+		result.Status, result.Error = models.HealthDown, err.Error()
+		return result
+	}
+
+	result.Details = map[string]interface{}{"current": current, "expected": expected}
+	if current != expected {
+		result.Status = models.HealthDown
+		result.Error = fmt.Sprintf("schema version %d does not match expected version %d", current, expected)
+	}
+	return result
+}
+
+// checkEnrichment проверяет внешний API: состояние выключателя и, если probe равен true,
+// доступность API. Проверка не критична: без API сервис работает с ограничениями.
+func (h *HealthHandler) checkEnrichment(ctx context.Context, probe bool) models.DependencyStatus {
+	result := models.DependencyStatus{Status: models.HealthOK}
+	breaker := h.Upstream.Breaker()
+	state := breaker.State()
+	result.Details = map[string]interface{}{"circuit": state}
+
+	if state == providers.CircuitOpen {
+		result.Status = models.HealthDegraded
+		result.Error = "circuit breaker is open"
+		result.Details["retry_after_seconds"] = breaker.RetryAfter().Seconds()
+		return result
+	}
+	if probe {
+		if err := h.Upstream.Ping(ctx); err != nil {
+			result.Status, result.Error = models.HealthDegraded, err.Error()
+		}
+	}
+	return result
+}
+
+// overallStatus сводит результаты проверок: down, если не прошла критичная проверка,
+// degraded, если не прошла некритичная, иначе ok.
+func overallStatus(checks map[string]models.DependencyStatus) string {
+	status := models.HealthOK
+	for _, check := range checks {
+		if check.Status == models.HealthOK {
+			continue
+		}
+		if check.Critical {
+			return models.HealthDown
+		}
+		status = models.HealthDegraded
+	}
+	return status
+}
+
+// milliseconds переводит длительность в миллисекунды с дробной частью
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"math"
+	"music-library/config"
+	"music-library/models"
+	"net/http"
+	"strconv"
+)
+
+// pagination содержит проверенные параметры страницы
+type pagination struct {
+	Page  int // Номер страницы (начиная с 1)
+	Limit int // Количество записей на странице
+}
+
+// offset возвращает количество записей, предшествующих странице
+func (p pagination) offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// pages возвращает общее количество страниц для total записей
+func (p pagination) pages(total int) int {
+	return (total + p.Limit - 1) / p.Limit
+}
+
+// window возвращает границы страницы [start, end) в списке из total элементов.
+// Для страницы за пределами списка возвращается пустой интервал.
+func (p pagination) window(total int) (int, int) {
+	start := p.offset()
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := start + p.Limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// paramError описывает ошибку в параметре запроса
+type paramError struct {
+	Field   string // Имя параметра
+	Message string // Описание ошибки
+}
+
+func (e *paramError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// parsePagination разбирает и проверяет параметры page и limit.
+// Размер страницы по умолчанию и максимальный размер задаются конфигурацией limits.
+func parsePagination(c *gin.Context, limits config.PaginationConfig) (pagination, *paramError) {
+	maxLimit, defaultLimit := limits.MaxLimit, limits.DefaultLimit
+
+	page, err := parsePositiveQuery(c, "page", 1)
+	if err != nil {
+		return pagination{}, err
+	}
+	limit, err := parsePositiveQuery(c, "limit", defaultLimit)
+	if err != nil {
+		return pagination{}, err
+	}
+	if limit > maxLimit {
+		return pagination{}, &paramError{Field: "limit", Message: fmt.Sprintf("must not exceed %d", maxLimit)}
+	}
+	// Смещение (page-1)*limit должно помещаться в int
+	if page-1 > math.MaxInt/limit {
+		return pagination{}, &paramError{Field: "page", Message: "is too large"}
+	}
+
+	return pagination{Page: page, Limit: limit}, nil
+}
+
+// parsePositiveQuery разбирает необязательный параметр запроса как целое число больше нуля
+func parsePositiveQuery(c *gin.Context, name string, fallback int) (int, *paramError) {
+	raw, ok := c.GetQuery(name)
+	if !ok {
+		return fallback, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, &paramError{Field: name, Message: "must be an integer"}
+	}
+	if value < 1 {
+		return 0, &paramError{Field: name, Message: "must be greater than zero"}
+	}
+	return value, nil
+}
+
+// respondParamError отправляет структурированный ответ 400 для ошибки в параметре запроса
+func respondParamError(c *gin.Context, err *paramError) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:   "invalid_parameter",
+		Field:   err.Field,
+		Message: err.Message,
+	})
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPaginationWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		pager     pagination
+		total     int
+		wantStart int
+		wantEnd   int
+	}{
+		{name: "first page", pager: pagination{Page: 1, Limit: 3}, total: 5, wantStart: 0, wantEnd: 3},
+		{name: "last partial page", pager: pagination{Page: 2, Limit: 3}, total: 5, wantStart: 3, wantEnd: 5},
+		{name: "page past the end", pager: pagination{Page: 3, Limit: 3}, total: 5, wantStart: 5, wantEnd: 5},
+		{name: "empty list", pager: pagination{Page: 1, Limit: 3}, total: 0, wantStart: 0, wantEnd: 0},
+		{name: "overflowed offset", pager: pagination{Page: math.MaxInt/4 + 2, Limit: 4}, total: 5, wantStart: 0, wantEnd: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.pager.window(tt.total)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("window(%d) = [%d, %d), want [%d, %d)", tt.total, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"log"
+	"music-library/config"
+	"music-library/models"
+	"music-library/repository"
+	"net/http"
+	"strings"
+)
+
+// PlaylistHandler обрабатывает запросы к плейлистам и их записям.
+type PlaylistHandler struct {
+	Playlists  *repository.PlaylistRepository // Репозиторий плейлистов
+	Pagination config.PaginationConfig        // Ограничения размера страницы
+}
+
+// NewPlaylistHandler создает обработчик с указанным репозиторием и ограничениями размера страницы
+func NewPlaylistHandler(playlists *repository.PlaylistRepository, pagination config.PaginationConfig) *PlaylistHandler {
+	return &PlaylistHandler{Playlists: playlists, Pagination: pagination}
+}
+
+// GetPlaylists возвращает список плейлистов с пагинацией.
+// Без параметра owner возвращаются только публичные плейлисты,
+// с параметром owner — все плейлисты владельца (visibility уточняет отбор).
+func (h *PlaylistHandler) GetPlaylists(c *gin.Context) {
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	filter := repository.PlaylistFilter{
+		Owner:      strings.TrimSpace(c.Query("owner")),
+		Visibility: c.Query("visibility"),
+	}
+	switch filter.Visibility {
+	case "", models.VisibilityPrivate, models.VisibilityPublic:
+	default:
+		respondParamError(c, &paramError{Field: "visibility", Message: "must be private or public"})
+		return
+	}
+	if filter.Owner == "" {
+		if filter.Visibility == models.VisibilityPrivate {
+			respondParamError(c, &paramError{Field: "visibility", Message: "private playlists can only be listed with owner"})
+			return
+		}
+		filter.Visibility = models.VisibilityPublic
+	}
+
+	playlists, total, err := h.Playlists.GetPlaylists(c.Request.Context(), filter, pager.Page, pager.Limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PlaylistListResponse{
+		Playlists: playlists,
+		Total:     total,
+		Page:      pager.Page,
+		Limit:     pager.Limit,
+	})
+}
+
+// GetPlaylist возвращает плейлист с записями по порядку
+func (h *PlaylistHandler) GetPlaylist(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	playlist, err := h.Playlists.GetPlaylistByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve playlist with ID %d: %v", id, err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// CreatePlaylist создает пустой плейлист
+func (h *PlaylistHandler) CreatePlaylist(c *gin.Context) {
+	var request models.PlaylistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid playlist data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	playlist := models.Playlist{}
+	if err := applyPlaylistRequest(&playlist, request); err != nil {
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	created, err := h.Playlists.CreatePlaylist(c.Request.Context(), &playlist)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/playlists/%d", created.ID))
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdatePlaylist обновляет владельца, название, описание и видимость плейлиста
+func (h *PlaylistHandler) UpdatePlaylist(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	playlist, err := h.Playlists.GetPlaylistByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	var request models.PlaylistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid playlist data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+	if err := applyPlaylistRequest(playlist, request); err != nil {
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	updated, err := h.Playlists.UpdatePlaylist(c.Request.Context(), playlist)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeletePlaylist удаляет плейлист вместе с записями
+func (h *PlaylistHandler) DeletePlaylist(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Playlists.DeletePlaylist(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// AddPlaylistEntry добавляет песню в конец плейлиста или вставляет на указанную позицию
+func (h *PlaylistHandler) AddPlaylistEntry(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	var request models.PlaylistEntryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid playlist entry data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	entry, err := h.Playlists.AddEntry(c.Request.Context(), id, request.SongID, request.Position)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case errors.Is(err, repository.ErrPlaylistSongNotFound):
+		c.String(http.StatusUnprocessableEntity, "song not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.JSON(http.StatusCreated, entry)
+	}
+}
+
+// MovePlaylistEntry перемещает запись плейлиста на новую позицию
+func (h *PlaylistHandler) MovePlaylistEntry(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+	entryID, paramErr := parseNamedIDParam(c, "entry_id")
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	var request models.MovePlaylistEntryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid playlist entry data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	playlist, err := h.Playlists.MoveEntry(c.Request.Context(), id, entryID, request.Position)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.JSON(http.StatusOK, playlist)
+	}
+}
+
+// RemovePlaylistEntry удаляет запись из плейлиста
+func (h *PlaylistHandler) RemovePlaylistEntry(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+	entryID, paramErr := parseNamedIDParam(c, "entry_id")
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Playlists.RemoveEntry(c.Request.Context(), id, entryID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// DuplicatePlaylist создает копию плейлиста со всеми записями
+func (h *PlaylistHandler) DuplicatePlaylist(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	var request models.DuplicatePlaylistRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			log.Printf("ERROR: Invalid playlist data: %v", err)
+			c.String(http.StatusBadRequest, "invalid input: %v", err)
+			return
+		}
+	}
+
+	duplicate, err := h.Playlists.DuplicatePlaylist(c.Request.Context(), id, strings.TrimSpace(request.Owner), strings.TrimSpace(request.Name), request.Visibility)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Header("Location", fmt.Sprintf("/playlists/%d", duplicate.ID))
+		c.JSON(http.StatusCreated, duplicate)
+	}
+}
+
+// applyPlaylistRequest переносит поля запроса в модель плейлиста
+func applyPlaylistRequest(playlist *models.Playlist, request models.PlaylistRequest) error {
+	owner, name := strings.TrimSpace(request.Owner), strings.TrimSpace(request.Name)
+	if owner == "" || name == "" {
+		return errors.New("owner and name must not be blank")
+	}
+
+	playlist.Owner = owner
+	playlist.Name = name
+	playlist.Description = strings.TrimSpace(request.Description)
+	playlist.Visibility = request.Visibility
+	if playlist.Visibility == "" {
+		playlist.Visibility = models.VisibilityPrivate
+	}
+	return nil
+}
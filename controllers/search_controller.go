@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"music-library/config"
+	"music-library/models"
+	"music-library/repository"
+	"net/http"
+	"strings"
+)
+
+// SearchHandler обрабатывает запросы к полнотекстовому поиску песен.
+type SearchHandler struct {
+	Repo            *repository.SearchRepository // Репозиторий поиска
+	Pagination      config.PaginationConfig      // Ограничения размера страницы
+	DefaultLanguage string                       // Конфигурация поиска, если параметр lang не задан
+}
+
+// NewSearchHandler создает обработчик с указанным репозиторием и настройками
+func NewSearchHandler(repo *repository.SearchRepository, pagination config.PaginationConfig, search config.SearchConfig) *SearchHandler {
+	return &SearchHandler{Repo: repo, Pagination: pagination, DefaultLanguage: search.DefaultLanguage}
+}
+
+// Search выполняет полнотекстовый поиск по группе, названию и тексту песен.
+// Параметры: q — запрос, mode — web (по умолчанию), plain, phrase или prefix,
+// lang — конфигурация поиска (по умолчанию search.default_language), page и limit.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := repository.SearchQuery{
+		Text:     strings.TrimSpace(c.Query("q")),
+		Mode:     strings.ToLower(c.DefaultQuery("mode", repository.SearchModeWeb)),
+		Language: strings.ToLower(c.DefaultQuery("lang", h.DefaultLanguage)),
+	}
+	if query.Text == "" {
+		respondParamError(c, &paramError{Field: "q", Message: "must not be empty"})
+		return
+	}
+	switch query.Mode {
+	case repository.SearchModeWeb, repository.SearchModePlain, repository.SearchModePhrase, repository.SearchModePrefix:
+	default:
+		respondParamError(c, &paramError{Field: "mode", Message: "must be one of: web, plain, phrase, prefix"})
+		return
+	}
+	if !models.IsSearchLanguage(query.Language) {
+		respondParamError(c, &paramError{Field: "lang", Message: "unsupported text search language"})
+		return
+	}
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	results, total, err := h.Repo.Search(c.Request.Context(), query, pager.Page, pager.Limit)
+	if errors.Is(err, repository.ErrEmptySearchQuery) {
+		respondParamError(c, &paramError{Field: "q", Message: err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Search for %q failed: %v", query.Text, err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SearchResponse{
+		Query:    query.Text,
+		Mode:     query.Mode,
+		Language: query.Language,
+		Results:  results,
+		Total:    total,
+		Page:     pager.Page,
+		Limit:    pager.Limit,
+	})
+}
@@ -1,33 +1,47 @@
 package controllers
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
-	"io"
+	"golang.org/x/sync/singleflight"
 	"log"
+	"music-library/config"
+	"music-library/lyrics"
+	"music-library/metrics"
 	"music-library/models"
+	"music-library/normalize"
+	"music-library/providers"
+	"music-library/repository"
 	"net/http"
-	"net/url"
-	"os"
-	"strconv"
 	"strings"
 	"time"
 )
 
-// SongEnrichment структура для данных, обогащающих информацию о песне
-type SongEnrichment struct {
-	Group       string `json:"group"`        // Группа исполнителей
-	Song        string `json:"song"`         // Название песни
-	ReleaseDate string `json:"release_date"` // Дата релиза песни
-	Text        string `json:"text"`         // Текст песни
-	Link        string `json:"link"`         // Ссылка на внешний источник
+// errInvalidReleaseDate — источник вернул дату релиза в неверном формате
+var errInvalidReleaseDate = errors.New("invalid release date")
+
+// errSongStorage — не удалось сохранить найденную песню в базе данных
+var errSongStorage = errors.New("failed to store song")
+
+// SongHandler обрабатывает запросы к песням и корзине.
+type SongHandler struct {
+	Songs      repository.SongStore    // Хранилище песен
+	Enrichment *providers.Chain        // Упорядоченная цепочка источников информации о песнях
+	Pagination config.PaginationConfig // Ограничения размера страницы
+	Fuzzy      config.FuzzyConfig      // Пороги нечеткого поиска в GET /info
+
+	lookups singleflight.Group // Объединяет параллельные запросы к источникам для одной и той же песни
+}
+
+// NewSongHandler создает обработчик песен с указанным хранилищем, цепочкой источников и настройками
+func NewSongHandler(songs repository.SongStore, enrichment *providers.Chain, pagination config.PaginationConfig, fuzzy config.FuzzyConfig) *SongHandler {
+	return &SongHandler{Songs: songs, Enrichment: enrichment, Pagination: pagination, Fuzzy: fuzzy}
 }
 
 // GetSongInfo обрабатывает запросы для получения информации о песне и добавляет её в базу данных
-func GetSongInfo(c *gin.Context) {
+func (h *SongHandler) GetSongInfo(c *gin.Context) {
 	group := c.Query("group") // Получаем название группы из параметров запроса
 	song := c.Query("song")   // Получаем название песни из параметров запроса
 
@@ -38,268 +52,428 @@ func GetSongInfo(c *gin.Context) {
 		return
 	}
 
-	var songRecord models.Song
-	// Используем db из контекста (db передается в main)
-	db := c.MustGet("db").(*gorm.DB)
-
-	// Поиск песни в базе данных
-	err := db.Where("\"group\" = ? AND song = ?", group, song).First(&songRecord).Error
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("INFO: Song '%s' by '%s' not found in database.", song, group)
-
-		// Запрос к внешнему API для получения информации о песне
-		songDetail, shouldReturn := GetSongDetailFromAPI(group, song, c)
-		if shouldReturn {
-			return
-		}
+	ctx := c.Request.Context()
 
-		// Конвертируем ReleaseDate из строки в time.Time
-		releaseDate, err := time.Parse("2006-01-02", songDetail.ReleaseDate) // Подгоните формат под ваш случай
+	// Поиск песни в базе данных без учета регистра, пробелов и диакритики,
+	// а при неудаче — нечеткий поиск похожей песни (если он не отключен параметром fuzzy=false)
+	var match *models.SongMatch
+	songRecord, err := h.Songs.FindSongByGroupAndSong(ctx, group, song)
+	if errors.Is(err, repository.ErrNotFound) && c.DefaultQuery("fuzzy", "true") != "false" {
+		var suggestions []models.SongMatch
+		match, suggestions, err = h.findSimilarSong(ctx, group, song)
 		if err != nil {
-			log.Printf("ERROR: Failed to parse release date: %v", err)
+			log.Printf("ERROR: Fuzzy lookup failed: %v", err)
+			metrics.EnrichmentLookups.WithLabelValues(metrics.LookupFailed).Inc()
 			c.String(http.StatusInternalServerError, "internal server error")
 			return
 		}
-
-		// Создание новой записи о песне
-		newSong := models.Song{
-			Group:       group,
-			Song:        song,
-			ReleaseDate: releaseDate, // Присваиваем time.Time
-			Text:        songDetail.Text,
-			Link:        songDetail.Link,
+		if match != nil {
+			log.Printf("INFO: Song '%s' by '%s' matched existing song ID %d with similarity %.2f", song, group, match.ID, match.Similarity)
+			songRecord, err = h.Songs.GetSongByID(ctx, uint(match.ID))
+		} else if len(suggestions) > 0 {
+			log.Printf("INFO: Song '%s' by '%s' not found, returning %d suggestions", song, group, len(suggestions))
+			c.JSON(http.StatusMultipleChoices, models.SongSuggestionsResponse{
+				Error:       "ambiguous_song",
+				Message:     "song not found, did you mean one of the suggestions? Repeat the request with fuzzy=false to look it up in external sources",
+				Suggestions: suggestions,
+			})
+			return
+		} else {
+			err = repository.ErrNotFound
 		}
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Printf("INFO: Song '%s' by '%s' not found in database.", song, group)
 
-		// Сохраняем новую песню в базу данных
-		if err := db.Create(&newSong).Error; err != nil {
+		// Параллельные запросы одной и той же песни выполняют один запрос к источникам.
+		// Запрос не отменяется вместе с первым клиентом, так как его результат ждут остальные.
+		key := normalize.Fold(group) + "\x00" + normalize.Fold(song)
+		result, err, shared := h.lookups.Do(key, func() (interface{}, error) {
+			return h.fetchAndStoreSong(context.WithoutCancel(ctx), group, song)
+		})
+		if shared {
+			log.Printf("INFO: Lookup of song '%s' by '%s' was shared with concurrent requests", song, group)
+		}
+		metrics.EnrichmentLookups.WithLabelValues(lookupOutcome(result, err)).Inc()
+		switch {
+		case errors.Is(err, providers.ErrSongNotFound):
+			log.Printf("INFO: Song '%s' by '%s' not found in any enrichment source.", song, group)
+			c.String(http.StatusNotFound, "song not found")
+		case errors.Is(err, providers.ErrCircuitOpen):
+			log.Printf("WARNING: External API is unavailable, circuit breaker is open")
+			c.String(http.StatusServiceUnavailable, "external API is temporarily unavailable, try again later")
+		case errors.Is(err, errInvalidReleaseDate):
+			log.Printf("ERROR: %v", err)
+			c.String(http.StatusBadGateway, "invalid release date received from enrichment source")
+		case errors.Is(err, errSongStorage):
 			log.Printf("ERROR: Failed to add new song to the database: %v", err)
 			c.String(http.StatusInternalServerError, "internal server error")
-			return
+		case err != nil:
+			log.Printf("ERROR: Failed to retrieve song details from external API: %v", err)
+			c.String(http.StatusBadGateway, "failed to retrieve song details from external API")
+		default:
+			c.JSON(http.StatusOK, result.(models.SongDetail))
 		}
-
-		log.Printf("INFO: Added new song to the database: %v", newSong)
-		songRecord = newSong
+		return
 	} else if err != nil {
 		log.Printf("ERROR: Database error: %v", err)
+		metrics.EnrichmentLookups.WithLabelValues(metrics.LookupFailed).Inc()
 		c.String(http.StatusInternalServerError, "internal server error")
 		return
 	}
+	metrics.EnrichmentLookups.WithLabelValues(metrics.LookupDatabase).Inc()
 
-	// Формируем ответ с деталями песни
-	songDetail := models.SongDetail{
-		ReleaseDate: songRecord.ReleaseDate.Format("2006-01-02"), // Форматируем в строку для ответа
-		Text:        songRecord.Text,
-		Link:        songRecord.Link,
-	}
+	// Формируем ответ с деталями песни; данные из базы имеют наивысший приоритет
+	songDetail := songDetailFromRecord(songRecord)
 
-	// Дополнительное обогащение данных из файла
-	enrichSongFromJSON(&songDetail, group, song)
+	// Незаполненные в базе поля дополняем из цепочки источников
+	enriched, err := h.Enrichment.Enrich(ctx, songRecord.Group, songRecord.Song, songDetail)
+	if err != nil && !errors.Is(err, providers.ErrSongNotFound) {
+		log.Printf("WARNING: Failed to enrich song '%s' by '%s': %v", song, group, err)
+	}
+	if err == nil {
+		songDetail = enriched
+	}
+	songDetail.Match = match
 	c.JSON(http.StatusOK, songDetail)
 }
 
-// GetSongs возвращает список всех песен из базы данных
-func GetSongs(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-	var songs []models.Song
-
-	// Получаем все песни из базы данных
-	if err := db.Find(&songs).Error; err != nil {
-		log.Printf("ERROR: Failed to fetch songs: %v", err)
-		c.String(http.StatusInternalServerError, "internal server error")
-		return
+// lookupOutcome определяет результат поиска песни в источниках для метрик: внешний API,
+// если из него взято хотя бы одно поле, иначе локальный файл (или база, если песню
+// параллельно сохранил другой экземпляр приложения).
+func lookupOutcome(result interface{}, err error) string {
+	switch {
+	case errors.Is(err, providers.ErrSongNotFound):
+		return metrics.LookupNotFound
+	case err != nil:
+		return metrics.LookupFailed
 	}
 
-	// Отправляем список песен в ответе
-	c.JSON(http.StatusOK, songs)
+	outcome := metrics.LookupDatabase
+	for _, source := range result.(models.SongDetail).Sources {
+		switch source {
+		case providers.SourceUpstreamAPI:
+			return metrics.LookupUpstream
+		case providers.SourceLocalFile:
+			outcome = metrics.LookupLocalFile
+		}
+	}
+	return outcome
 }
 
-// GetSongTextWithPagination возвращает текст песни с пагинацией
-func GetSongTextWithPagination(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-	songID := c.Param("id") // Получаем ID песни из параметров маршрута
-
-	// Получаем песню по ID
-	var song models.Song
-	if err := db.First(&song, songID).Error; err != nil {
-		log.Printf("ERROR: Song not found with ID: %v", songID)
-		c.String(http.StatusNotFound, "Song not found")
-		return
+// fetchAndStoreSong получает информацию о песне из цепочки источников и сохраняет песню в базе.
+// Если песню успел сохранить другой экземпляр приложения, возвращаются данные уже сохраненной песни.
+func (h *SongHandler) fetchAndStoreSong(ctx context.Context, group, song string) (models.SongDetail, error) {
+	songDetail, err := h.Enrichment.Enrich(ctx, group, song, models.SongDetail{})
+	if err != nil {
+		return models.SongDetail{}, err
 	}
 
-	// Разбиваем текст песни на строки или части (например, по строкам)
-	verses := splitTextIntoParts(song.Text)
-
-	// Пагинация
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	// Конвертируем ReleaseDate из строки в time.Time (дата может отсутствовать во всех источниках)
+	var releaseDate time.Time
+	if songDetail.ReleaseDate != "" {
+		releaseDate, err = time.Parse("2006-01-02", songDetail.ReleaseDate)
+		if err != nil {
+			return models.SongDetail{}, fmt.Errorf("%w from %s: %w", errInvalidReleaseDate, songDetail.Sources[providers.FieldReleaseDate], err)
+		}
+	}
 
-	// Получаем подмножество текста с учетом пагинации
-	start := (page - 1) * limit
-	end := start + limit
-	if end > len(verses) {
-		end = len(verses)
+	stored, created, err := h.Songs.GetOrCreateSong(ctx, &models.Song{
+		Group:       group,
+		Song:        song,
+		ReleaseDate: releaseDate,
+		Text:        songDetail.Text,
+		Link:        songDetail.Link,
+	})
+	if err != nil {
+		return models.SongDetail{}, fmt.Errorf("%w: %w", errSongStorage, err)
+	}
+	if !created {
+		log.Printf("INFO: Song '%s' by '%s' was added concurrently, using song ID %d", song, group, stored.ID)
+		return songDetailFromRecord(stored), nil
 	}
 
-	// Возвращаем выбранные части текста
-	c.JSON(http.StatusOK, verses[start:end])
+	log.Printf("INFO: Added new song to the database: %v", *stored)
+	return songDetail, nil
 }
 
-// splitTextIntoParts разделяет текст на части (например, по строкам)
-func splitTextIntoParts(text string) []string {
-	// Разделяем текст по строкам
-	return strings.Split(text, "\n")
+// songDetailFromRecord формирует детали песни из записи в базе данных
+func songDetailFromRecord(song *models.Song) models.SongDetail {
+	songDetail := models.SongDetail{
+		Text: song.Text,
+		Link: song.Link,
+	}
+	if !song.ReleaseDate.IsZero() {
+		songDetail.ReleaseDate = song.ReleaseDate.Format("2006-01-02") // Форматируем в строку для ответа
+	}
+	songDetail.Sources = providers.DetailSources(songDetail, providers.SourceDatabase)
+	return songDetail
 }
 
-// GetSongDetailFromJSON получает информацию о песне из JSON файла
-func GetSongDetailFromJSON(group, song string) (models.SongDetail, error) {
-	// Открываем JSON файл
-	jsonFile, err := os.Open("song_enrichment.json")
+// findSimilarSong ищет песню, похожую на запрошенную. Если сходство лучшего кандидата не ниже
+// fuzzy.match_threshold, он возвращается как совпадение; иначе возвращаются кандидаты
+// со сходством не ниже fuzzy.suggest_threshold в качестве подсказок.
+func (h *SongHandler) findSimilarSong(ctx context.Context, group, song string) (*models.SongMatch, []models.SongMatch, error) {
+	candidates, err := h.Songs.FindSimilarSongs(ctx, group, song, h.Fuzzy.Suggestions)
 	if err != nil {
-		return models.SongDetail{}, fmt.Errorf("не удалось открыть JSON файл: %v", err)
+		return nil, nil, err
 	}
-	defer jsonFile.Close()
 
-	// Читаем данные из файла
-	byteValue, err := io.ReadAll(jsonFile)
-	if err != nil {
-		return models.SongDetail{}, fmt.Errorf("не удалось прочитать JSON файл: %v", err)
+	if len(candidates) > 0 && candidates[0].Similarity >= h.Fuzzy.MatchThreshold {
+		return &candidates[0], nil, nil
 	}
 
-	var enrichmentData SongEnrichment
-	if err := json.Unmarshal(byteValue, &enrichmentData); err != nil {
-		return models.SongDetail{}, fmt.Errorf("не удалось разобрать JSON файл: %v", err)
+	suggestions := make([]models.SongMatch, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Similarity >= h.Fuzzy.SuggestThreshold {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return nil, suggestions, nil
+}
+
+// GetSongs возвращает список песен с фильтрацией, сортировкой и пагинацией
+func (h *SongHandler) GetSongs(c *gin.Context) {
+	// Разбираем фильтры, сортировку и пагинацию из параметров запроса
+	filter, paramErr := parseSongFilter(c)
+	if paramErr != nil {
+		log.Printf("ERROR: Invalid song filter: %v", paramErr)
+		respondParamError(c, paramErr)
+		return
+	}
+	sort, paramErr := parseSongSort(c)
+	if paramErr != nil {
+		log.Printf("ERROR: Invalid song sort: %v", paramErr)
+		respondParamError(c, paramErr)
+		return
+	}
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		log.Printf("ERROR: Invalid pagination: %v", paramErr)
+		respondParamError(c, paramErr)
+		return
+	}
+	limit := pager.Limit
+
+	var err error
+	response := models.SongListResponse{Limit: limit}
+	if token, ok := c.GetQuery("cursor"); ok {
+		// Курсорная пагинация: пустой курсор означает начало списка
+		var cursor *repository.SongCursor
+		if token != "" {
+			if cursor, err = repository.DecodeSongCursor(token, sort); err != nil {
+				log.Printf("ERROR: Invalid cursor: %v", err)
+				respondParamError(c, &paramError{Field: "cursor", Message: err.Error()})
+				return
+			}
+		}
+		response.Songs, response.Total, err = h.Songs.GetSongsAfter(c.Request.Context(), filter, sort, cursor, limit)
+	} else {
+		response.Page = pager.Page
+		response.Songs, response.Total, err = h.Songs.GetAllSongs(c.Request.Context(), filter, sort, pager.Page, limit)
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch songs: %v", err)
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
 	}
 
-	// Если группа и песня совпадают, возвращаем данные
-	if enrichmentData.Group == group && enrichmentData.Song == song {
-		return models.SongDetail{
-			ReleaseDate: enrichmentData.ReleaseDate,
-			Text:        enrichmentData.Text,
-			Link:        enrichmentData.Link,
-		}, nil
+	// Полная страница означает, что дальше могут быть еще песни
+	if len(response.Songs) == limit {
+		response.NextCursor = repository.NewSongCursor(response.Songs[len(response.Songs)-1], sort).Encode()
 	}
 
-	return models.SongDetail{}, fmt.Errorf("песня не найдена в JSON файле")
+	// Отправляем страницу песен вместе с общим количеством
+	c.JSON(http.StatusOK, response)
 }
 
-// GetSongDetailFromAPI выполняет запрос к внешнему API
-func GetSongDetailFromAPI(group, song string, c *gin.Context) (models.SongDetail, bool) {
-	encodedGroup := url.QueryEscape(group)
-	encodedSong := url.QueryEscape(song)
-	apiURL := fmt.Sprintf("http://localhost:8081/info?group=%s&song=%s", encodedGroup, encodedSong)
+// CreateSong создает новую песню без обращения к внешнему API
+func (h *SongHandler) CreateSong(c *gin.Context) {
+	var request models.CreateSongRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid song data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
 
-	// Запрос к внешнему API
-	response, err := http.Get(apiURL)
-	if err != nil {
-		log.Printf("ERROR: Failed to request external API: %v", err)
-		c.String(http.StatusInternalServerError, "internal server error")
-		return models.SongDetail{}, true
+	group := strings.TrimSpace(request.Group)
+	title := strings.TrimSpace(request.Song)
+	if group == "" || title == "" {
+		log.Println("ERROR: Empty 'group' or 'song' in request body")
+		c.String(http.StatusBadRequest, "invalid input: group and song must not be blank")
+		return
 	}
-	defer response.Body.Close()
 
-	// Проверка статуса ответа
-	if response.StatusCode != http.StatusOK {
-		log.Printf("WARNING: External API returned status code %d", response.StatusCode)
-		c.String(http.StatusInternalServerError, "failed to retrieve song details from external API")
-		return models.SongDetail{}, true
+	if request.Language != "" && !models.IsSearchLanguage(request.Language) {
+		c.String(http.StatusBadRequest, "invalid input: unsupported language %q", request.Language)
+		return
 	}
 
-	var apiData models.SongDetail
-	body, err := io.ReadAll(response.Body)
+	// Дата уже проверена валидатором, поэтому ошибка разбора здесь не ожидается
+	releaseDate, err := time.Parse("2006-01-02", request.ReleaseDate)
 	if err != nil {
-		log.Printf("ERROR: Failed to read API response: %v", err)
+		log.Printf("ERROR: Failed to parse release date: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: release_date must be in YYYY-MM-DD format")
+		return
+	}
+
+	// Проверяем, что такой пары группа+песня еще нет в базе
+	_, err = h.Songs.FindSongByGroupAndSong(c.Request.Context(), group, title)
+	if err == nil {
+		log.Printf("INFO: Song '%s' by '%s' already exists.", title, group)
+		c.String(http.StatusConflict, "song already exists")
+		return
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("ERROR: Database error: %v", err)
 		c.String(http.StatusInternalServerError, "internal server error")
-		return models.SongDetail{}, true
+		return
 	}
 
-	if err := json.Unmarshal(body, &apiData); err != nil {
-		log.Printf("ERROR: Failed to parse API response: %v", err)
+	newSong, err := h.Songs.SaveSong(c.Request.Context(), &models.Song{
+		Group:       group,
+		Song:        title,
+		ReleaseDate: releaseDate,
+		Text:        request.Text,
+		Link:        request.Link,
+		Language:    request.Language,
+	})
+	if errors.Is(err, repository.ErrDuplicateSong) {
+		// Песню успели добавить параллельным запросом
+		c.String(http.StatusConflict, "song already exists")
+		return
+	}
+	if err != nil {
 		c.String(http.StatusInternalServerError, "internal server error")
-		return models.SongDetail{}, true
+		return
 	}
 
-	return apiData, false
+	c.Header("Location", fmt.Sprintf("/songs/%d", newSong.ID))
+	c.JSON(http.StatusCreated, newSong)
 }
 
-// enrichSongFromJSON читает данные из JSON-файла и обогащает информацию о песне
-func enrichSongFromJSON(songDetail *models.SongDetail, group, song string) {
-	jsonFile, err := os.Open("song_enrichment.json")
-	if err != nil {
-		log.Printf("ERROR: Could not open JSON file: %v", err)
+// GetSongTextWithPagination возвращает текст песни с пагинацией по куплетам или строкам
+func (h *SongHandler) GetSongTextWithPagination(c *gin.Context) {
+	songID, paramErr := parseIDParam(c) // Получаем ID песни из параметров маршрута
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	// Режим разбиения: lines (по умолчанию, как до появления куплетов) или verses
+	mode := c.DefaultQuery("mode", lyrics.ModeLines)
+
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		log.Printf("ERROR: Invalid pagination: %v", paramErr)
+		respondParamError(c, paramErr)
 		return
 	}
-	defer jsonFile.Close()
 
-	byteValue, err := io.ReadAll(jsonFile)
+	// Получаем песню по ID
+	song, err := h.Songs.GetSongByID(c.Request.Context(), songID)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Printf("ERROR: Song not found with ID: %v", songID)
+		c.String(http.StatusNotFound, "Song not found")
+		return
+	}
 	if err != nil {
-		log.Printf("ERROR: Failed to read JSON file: %v", err)
+		c.String(http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	var enrichmentData SongEnrichment
-	if err := json.Unmarshal(byteValue, &enrichmentData); err != nil {
-		log.Printf("ERROR: Could not parse JSON file: %v", err)
+	// Разбиваем текст песни на куплеты или строки
+	parts, ok := lyrics.Split(song.Text, mode)
+	if !ok {
+		respondParamError(c, &paramError{Field: "mode", Message: "must be lines or verses"})
 		return
 	}
 
-	// Обогащение информации о песне
-	if enrichmentData.Group == group && enrichmentData.Song == song {
-		// Преобразуем release date в time.Time
-		releaseDate, err := time.Parse("2006-01-02", enrichmentData.ReleaseDate)
-		if err != nil {
-			log.Printf("ERROR: Failed to parse release date from enrichment data: %v", err)
-			return
-		}
-		// Присваиваем ReleaseDate как time.Time в songDetail
-		songDetail.ReleaseDate = releaseDate.Format("2006-01-02") // Преобразуем в строку для SongDetail
-		songDetail.Text = enrichmentData.Text
-		songDetail.Link = enrichmentData.Link
+	// Пагинация: страница за пределами текста возвращается пустой
+	start, end := pager.window(len(parts))
+	verses := make([]models.Verse, 0, end-start)
+	for i := start; i < end; i++ {
+		verses = append(verses, models.Verse{Index: i + 1, Text: parts[i]})
 	}
+
+	// Возвращаем выбранные части текста вместе с метаданными страницы
+	c.JSON(http.StatusOK, models.SongVersesResponse{
+		SongID: song.ID,
+		Mode:   mode,
+		Verses: verses,
+		Total:  len(parts),
+		Page:   pager.Page,
+		Limit:  pager.Limit,
+		Pages:  pager.pages(len(parts)),
+	})
 }
 
 // UpdateSong обновляет песню
-func UpdateSong(c *gin.Context) {
-	id := c.Param("id")
-	db := c.MustGet("db").(*gorm.DB)
+func (h *SongHandler) UpdateSong(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
 
-	var song models.Song
-	if err := db.First(&song, id).Error; err != nil {
-		log.Printf("ERROR: Song with ID %s not found", id)
+	song, err := h.Songs.GetSongByID(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Printf("ERROR: Song with ID %d not found", id)
 		c.String(http.StatusNotFound, "not found")
 		return
 	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
 
-	// Обработка JSON данных из запроса
-	if err := c.ShouldBindJSON(&song); err != nil {
+	// Обработка JSON данных из запроса; ID песни задается только маршрутом
+	if err := c.ShouldBindJSON(song); err != nil {
 		log.Printf("ERROR: Invalid song data: %v", err)
 		c.String(http.StatusBadRequest, "invalid input")
 		return
 	}
+	if song.Language != "" && !models.IsSearchLanguage(song.Language) {
+		c.String(http.StatusBadRequest, "invalid input: unsupported language %q", song.Language)
+		return
+	}
 
-	// Обновление записи в базе данных
-	if err := db.Transaction(func(tx *gorm.DB) error {
-		return tx.Save(&song).Error
-	}); err != nil {
-		log.Printf("ERROR: Failed to update song with ID %s: %v", id, err)
+	song.ID = int(id)
+
+	// Обновление записи в хранилище
+	if _, err := h.Songs.UpdateSong(c.Request.Context(), song); errors.Is(err, repository.ErrDuplicateSong) {
+		log.Printf("ERROR: Failed to update song with ID %d: %v", id, err)
+		c.String(http.StatusConflict, "song with the same group and title or album position already exists")
+		return
+	} else if err != nil {
+		log.Printf("ERROR: Failed to update song with ID %d: %v", id, err)
 		c.String(http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	log.Printf("INFO: Updated song with ID %s", id)
+	log.Printf("INFO: Updated song with ID %d", id)
 	c.JSON(http.StatusOK, song)
 }
 
-// DeleteSong удаляет песню
-func DeleteSong(c *gin.Context) {
-	id := c.Param("id")
-	db := c.MustGet("db").(*gorm.DB)
+// DeleteSong перемещает песню в корзину
+func (h *SongHandler) DeleteSong(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
 
-	if err := db.Delete(&models.Song{}, id).Error; err != nil {
-		log.Printf("ERROR: Failed to delete song with ID %s: %v", id, err)
+	err := h.Songs.DeleteSong(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Printf("ERROR: Song with ID %d not found", id)
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
 		c.String(http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	log.Printf("INFO: Deleted song with ID %s", id)
-	c.JSON(http.StatusOK, map[string]interface{}{"id #" + id: "deleted"})
+	log.Printf("INFO: Deleted song with ID %d", id)
+	c.JSON(http.StatusOK, map[string]interface{}{fmt.Sprintf("id #%d", id): "deleted"})
 }
@@ -0,0 +1,317 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"music-library/config"
+	"music-library/metrics"
+	"music-library/models"
+	"music-library/providers"
+	"music-library/repository"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestSongRouter создает маршрутизатор с обработчиком песен поверх хранилища в памяти.
+// Цепочка источников состоит из одного локального файла с песнями из enrichments.
+func newTestSongRouter(t *testing.T, store repository.SongStore, enrichments ...providers.SongEnrichment) (*gin.Engine, *SongHandler) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "enrichment.json")
+	data, err := json.Marshal(enrichments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	handler := NewSongHandler(store, providers.NewChain(providers.NewFileProvider(path)), cfg.Pagination, cfg.Fuzzy)
+
+	router := gin.New()
+	router.GET("/info", handler.GetSongInfo)
+	router.GET("/songs", handler.GetSongs)
+	router.POST("/songs", handler.CreateSong)
+	router.GET("/songs/:id/verses", handler.GetSongTextWithPagination)
+	router.PUT("/songs/:id", handler.UpdateSong)
+	router.DELETE("/songs/:id", handler.DeleteSong)
+	router.GET("/songs/trash", handler.GetTrashedSongs)
+	router.POST("/songs/:id/restore", handler.RestoreSong)
+	router.DELETE("/songs/:id/purge", handler.PurgeSong)
+	return router, handler
+}
+
+// serve выполняет запрос и возвращает записанный ответ.
+func serve(router *gin.Engine, method, target, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// decode разбирает JSON-ответ в value.
+func decode(t *testing.T, recorder *httptest.ResponseRecorder, value interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), value); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", recorder.Body.String(), err)
+	}
+}
+
+func testSong(group, song string) models.Song {
+	return models.Song{Group: group, Song: song, ReleaseDate: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestCreateSong(t *testing.T) {
+	router, _ := newTestSongRouter(t, repository.NewMemorySongStore(testSong("Muse", "Uprising")))
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "created", body: `{"group":"Queen","song":"Bohemian Rhapsody","release_date":"1975-10-31"}`, want: http.StatusCreated},
+		{name: "duplicate after normalization", body: `{"group":" muse ","song":"UPRISING","release_date":"2009-09-07"}`, want: http.StatusConflict},
+		{name: "missing release date", body: `{"group":"Queen","song":"Innuendo"}`, want: http.StatusBadRequest},
+		{name: "blank group", body: `{"group":"  ","song":"Innuendo","release_date":"1991-01-14"}`, want: http.StatusBadRequest},
+		{name: "unsupported language", body: `{"group":"Queen","song":"Innuendo","release_date":"1991-01-14","language":"klingon"}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(router, http.MethodPost, "/songs", tt.body); got.Code != tt.want {
+				t.Errorf("POST /songs status = %d, want %d: %s", got.Code, tt.want, got.Body)
+			}
+		})
+	}
+}
+
+func TestGetSongsPagination(t *testing.T) {
+	store := repository.NewMemorySongStore(
+		testSong("Muse", "Uprising"),
+		testSong("Muse", "Hysteria"),
+		testSong("Muse", "Starlight"),
+		testSong("Queen", "Innuendo"),
+	)
+	router, _ := newTestSongRouter(t, store)
+
+	t.Run("filter and offset", func(t *testing.T) {
+		recorder := serve(router, http.MethodGet, "/songs?group=muse&limit=2&page=2", "")
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+		}
+		var response models.SongListResponse
+		decode(t, recorder, &response)
+		if response.Total != 3 || len(response.Songs) != 1 {
+			t.Errorf("total = %d, songs = %d, want 3 and 1", response.Total, len(response.Songs))
+		}
+	})
+
+	t.Run("cursor walks all songs once", func(t *testing.T) {
+		seen := make(map[int]bool)
+		cursor := ""
+		for page := 0; page < 10; page++ {
+			recorder := serve(router, http.MethodGet, "/songs?limit=3&sort=song&cursor="+cursor, "")
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+			}
+			var response models.SongListResponse
+			decode(t, recorder, &response)
+			for _, song := range response.Songs {
+				if seen[song.ID] {
+					t.Fatalf("song %d returned twice", song.ID)
+				}
+				seen[song.ID] = true
+			}
+			if response.NextCursor == "" {
+				break
+			}
+			cursor = response.NextCursor
+		}
+		if len(seen) != 4 {
+			t.Errorf("cursor pagination returned %d songs, want 4", len(seen))
+		}
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, target := range []string{"/songs?limit=1000", "/songs?page=0", "/songs?cursor=garbage", "/songs?sort=unknown", "/songs?page=4611686018427387904&limit=4"} {
+			if recorder := serve(router, http.MethodGet, target, ""); recorder.Code != http.StatusBadRequest {
+				t.Errorf("GET %s status = %d, want 400", target, recorder.Code)
+			}
+		}
+	})
+}
+
+func TestGetSongInfo(t *testing.T) {
+	store := repository.NewMemorySongStore(testSong("Muse", "Uprising"), testSong("Muse", "Hysteria"))
+	router, _ := newTestSongRouter(t, store, providers.SongEnrichment{
+		Group: "Queen", Song: "Innuendo", ReleaseDate: "1991-01-14", Text: "While the sun hangs in the sky", Link: "https://example.com/innuendo",
+	})
+
+	tests := []struct {
+		name   string
+		query  string
+		want   int
+		source string // Ожидаемый источник поля text
+	}{
+		{name: "exact match from store", query: "group=MUSE&song=uprising", want: http.StatusOK, source: providers.SourceDatabase},
+		{name: "fuzzy match from store", query: "group=Muse&song=Uprisin", want: http.StatusOK, source: providers.SourceDatabase},
+		{name: "fetched from local file", query: "group=Queen&song=Innuendo", want: http.StatusOK, source: providers.SourceLocalFile},
+		{name: "not found anywhere", query: "group=Nobody&song=Nothing&fuzzy=false", want: http.StatusNotFound},
+		{name: "missing parameters", query: "group=Muse", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(router, http.MethodGet, "/info?"+tt.query, "")
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body)
+			}
+			if tt.source == "" {
+				return
+			}
+			var detail models.SongDetail
+			decode(t, recorder, &detail)
+			if detail.Sources[providers.FieldReleaseDate] != tt.source {
+				t.Errorf("release_date source = %q, want %q", detail.Sources[providers.FieldReleaseDate], tt.source)
+			}
+		})
+	}
+
+	// Песня из файла сохраняется и при повторном запросе берется из хранилища
+	if _, err := store.FindSongByGroupAndSong(context.Background(), "queen", "innuendo"); err != nil {
+		t.Errorf("fetched song was not stored: %v", err)
+	}
+}
+
+func TestGetSongInfoSuggestions(t *testing.T) {
+	store := repository.NewMemorySongStore(testSong("Muse", "Uprising"), testSong("Muse", "Undisclosed Desires"))
+	router, handler := newTestSongRouter(t, store)
+	handler.Fuzzy.MatchThreshold = 0.99 // Ни один кандидат не считается совпадением
+
+	recorder := serve(router, http.MethodGet, "/info?group=Muse&song=Up", "")
+	if recorder.Code != http.StatusMultipleChoices {
+		t.Fatalf("status = %d, want 300: %s", recorder.Code, recorder.Body)
+	}
+	var response models.SongSuggestionsResponse
+	decode(t, recorder, &response)
+	if len(response.Suggestions) == 0 || response.Suggestions[0].Song != "Uprising" {
+		t.Errorf("suggestions = %+v, want Uprising first", response.Suggestions)
+	}
+}
+
+func TestUpdateSongConflict(t *testing.T) {
+	store := repository.NewMemorySongStore(testSong("Muse", "Uprising"), testSong("Muse", "Hysteria"))
+	router, _ := newTestSongRouter(t, store)
+
+	body := `{"group":"Muse","song":"uprising","release_date":"2009-09-07T00:00:00Z"}`
+	if recorder := serve(router, http.MethodPut, "/songs/2", body); recorder.Code != http.StatusConflict {
+		t.Errorf("PUT /songs/2 status = %d, want 409: %s", recorder.Code, recorder.Body)
+	}
+	if recorder := serve(router, http.MethodPut, "/songs/99", body); recorder.Code != http.StatusNotFound {
+		t.Errorf("PUT /songs/99 status = %d, want 404", recorder.Code)
+	}
+}
+
+func TestTrashLifecycle(t *testing.T) {
+	store := repository.NewMemorySongStore(testSong("Muse", "Uprising"))
+	router, _ := newTestSongRouter(t, store)
+
+	steps := []struct {
+		method string
+		target string
+		want   int
+	}{
+		{http.MethodDelete, "/songs/1", http.StatusOK},
+		{http.MethodDelete, "/songs/1", http.StatusNotFound},
+		{http.MethodPost, "/songs/1/restore", http.StatusOK},
+		{http.MethodPost, "/songs/1/restore", http.StatusNotFound},
+		{http.MethodDelete, "/songs/1/purge", http.StatusConflict}, // Окончательно удаляются только песни из корзины
+		{http.MethodDelete, "/songs/1", http.StatusOK},
+		{http.MethodDelete, "/songs/1/purge", http.StatusNoContent},
+		{http.MethodPost, "/songs/1/restore", http.StatusNotFound},
+	}
+	for _, step := range steps {
+		if recorder := serve(router, step.method, step.target, ""); recorder.Code != step.want {
+			t.Fatalf("%s %s status = %d, want %d: %s", step.method, step.target, recorder.Code, step.want, recorder.Body)
+		}
+	}
+}
+
+func TestGetSongTextWithPagination(t *testing.T) {
+	song := testSong("Queen", "Bohemian Rhapsody")
+	song.Text = "Is this the real life?\nIs this just fantasy?\n\nMama, just killed a man"
+	router, _ := newTestSongRouter(t, repository.NewMemorySongStore(song))
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+		texts []string
+		total int
+	}{
+		{name: "lines by default", query: "limit=3", want: http.StatusOK, texts: []string{"Is this the real life?", "Is this just fantasy?", ""}, total: 4},
+		{name: "verses", query: "mode=verses&limit=1&page=2", want: http.StatusOK, texts: []string{"Mama, just killed a man"}, total: 2},
+		{name: "page past the end", query: "mode=verses&page=5", want: http.StatusOK, texts: []string{}, total: 2},
+		{name: "unknown mode", query: "mode=stanzas", want: http.StatusBadRequest},
+		{name: "offset overflow", query: "page=4611686018427387904&limit=4", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(router, http.MethodGet, "/songs/1/verses?"+tt.query, "")
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var response models.SongVersesResponse
+			decode(t, recorder, &response)
+			texts := make([]string, 0, len(response.Verses))
+			for _, verse := range response.Verses {
+				texts = append(texts, verse.Text)
+			}
+			if strings.Join(texts, "|") != strings.Join(tt.texts, "|") || response.Total != tt.total {
+				t.Errorf("verses = %q, total = %d, want %q and %d", texts, response.Total, tt.texts, tt.total)
+			}
+		})
+	}
+}
+
+func TestLookupOutcome(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]string
+		err     error
+		want    string
+	}{
+		{name: "not found", err: providers.ErrSongNotFound, want: metrics.LookupNotFound},
+		{name: "failed", err: errors.New("circuit open"), want: metrics.LookupFailed},
+		{name: "stored concurrently", sources: map[string]string{providers.FieldText: providers.SourceDatabase}, want: metrics.LookupDatabase},
+		{name: "local file", sources: map[string]string{providers.FieldText: providers.SourceLocalFile}, want: metrics.LookupLocalFile},
+		{
+			name:    "upstream wins over local file",
+			sources: map[string]string{providers.FieldText: providers.SourceLocalFile, providers.FieldLink: providers.SourceUpstreamAPI},
+			want:    metrics.LookupUpstream,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lookupOutcome(models.SongDetail{Sources: tt.sources}, tt.err); got != tt.want {
+				t.Errorf("lookupOutcome() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"music-library/repository"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseSongFilter собирает фильтр списка песен из параметров запроса
+func parseSongFilter(c *gin.Context) (repository.SongFilter, *paramError) {
+	filter := repository.SongFilter{
+		Group: strings.TrimSpace(c.Query("group")),
+		Song:  strings.TrimSpace(c.Query("song")),
+		Text:  strings.TrimSpace(c.Query("text")),
+		Link:  strings.TrimSpace(c.Query("link")),
+	}
+
+	artistID, paramErr := parseOptionalIDQuery(c, "artist_id")
+	if paramErr != nil {
+		return filter, paramErr
+	}
+	filter.ArtistID = artistID
+
+	genreID, paramErr := parseOptionalIDQuery(c, "genre_id")
+	if paramErr != nil {
+		return filter, paramErr
+	}
+	filter.GenreID = genreID
+
+	var tags []string
+	for _, raw := range c.QueryArray("tags") {
+		tags = append(tags, strings.Split(raw, ",")...)
+	}
+	filter.Tags = repository.NormalizeTags(tags)
+
+	switch strings.ToLower(c.DefaultQuery("tags_match", "any")) {
+	case "any":
+	case "all":
+		filter.AllTags = true
+	default:
+		return filter, &paramError{Field: "tags_match", Message: "must be any or all"}
+	}
+
+	if raw := c.Query("has_link"); raw != "" {
+		hasLink, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, &paramError{Field: "has_link", Message: "must be true or false"}
+		}
+		filter.HasLink = &hasLink
+	}
+
+	from, err := parseDateQuery(c, "release_date_from")
+	if err != nil {
+		return filter, err
+	}
+	filter.ReleaseDateFrom = from
+
+	to, err := parseDateQuery(c, "release_date_to")
+	if err != nil {
+		return filter, err
+	}
+	filter.ReleaseDateTo = to
+
+	if from != nil && to != nil && from.After(*to) {
+		return filter, &paramError{Field: "release_date_from", Message: "must not be after release_date_to"}
+	}
+	return filter, nil
+}
+
+// parseSongSort собирает параметры сортировки из запроса (sort и order)
+func parseSongSort(c *gin.Context) (repository.SongSort, *paramError) {
+	sort := repository.SongSort{Field: c.DefaultQuery("sort", "id")}
+	if _, ok := repository.SongSortFields[sort.Field]; !ok {
+		return sort, &paramError{Field: "sort", Message: "must be one of: id, group, song, text, release_date, link, created_at, updated_at"}
+	}
+
+	switch strings.ToLower(c.DefaultQuery("order", "asc")) {
+	case "asc":
+	case "desc":
+		sort.Desc = true
+	default:
+		return sort, &paramError{Field: "order", Message: "must be asc or desc"}
+	}
+	return sort, nil
+}
+
+// parseDateQuery разбирает необязательный параметр даты в формате YYYY-MM-DD
+func parseDateQuery(c *gin.Context, name string) (*time.Time, *paramError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, &paramError{Field: name, Message: "must be in YYYY-MM-DD format"}
+	}
+	return &date, nil
+}
+
+// parseIDParam разбирает параметр маршрута :id как положительное целое число
+func parseIDParam(c *gin.Context) (uint, *paramError) {
+	return parseNamedIDParam(c, "id")
+}
+
+// parseNamedIDParam разбирает параметр маршрута с указанным именем как положительное целое число
+func parseNamedIDParam(c *gin.Context, name string) (uint, *paramError) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 0)
+	if err != nil || id == 0 {
+		return 0, &paramError{Field: name, Message: "must be a positive integer"}
+	}
+	return uint(id), nil
+}
+
+// parseOptionalIDQuery разбирает необязательный query-параметр с идентификатором (nil, если он не задан)
+func parseOptionalIDQuery(c *gin.Context, name string) (*int, *paramError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id < 1 {
+		return nil, &paramError{Field: name, Message: "must be a positive integer"}
+	}
+	return &id, nil
+}
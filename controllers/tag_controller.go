@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"log"
+	"music-library/config"
+	"music-library/models"
+	"music-library/repository"
+	"net/http"
+	"strings"
+)
+
+// TagHandler обрабатывает запросы к тегам и тегам песен.
+type TagHandler struct {
+	Tags       *repository.TagRepository // Репозиторий тегов
+	Pagination config.PaginationConfig   // Ограничения размера страницы
+}
+
+// NewTagHandler создает обработчик с указанным репозиторием и ограничениями размера страницы
+func NewTagHandler(tags *repository.TagRepository, pagination config.PaginationConfig) *TagHandler {
+	return &TagHandler{Tags: tags, Pagination: pagination}
+}
+
+// GetTags возвращает список тегов с пагинацией и фильтром по имени
+func (h *TagHandler) GetTags(c *gin.Context) {
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	tags, total, err := h.Tags.GetTags(c.Request.Context(), strings.TrimSpace(c.Query("name")), pager.Page, pager.Limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TagListResponse{
+		Tags:  tags,
+		Total: total,
+		Page:  pager.Page,
+		Limit: pager.Limit,
+	})
+}
+
+// DeleteTag удаляет тег у всех песен
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Tags.DeleteTag(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// SetSongTags заменяет набор тегов песни
+func (h *TagHandler) SetSongTags(c *gin.Context) {
+	h.changeSongTags(c, true)
+}
+
+// AddSongTags добавляет теги песне
+func (h *TagHandler) AddSongTags(c *gin.Context) {
+	h.changeSongTags(c, false)
+}
+
+// RemoveSongTag снимает тег с песни
+func (h *TagHandler) RemoveSongTag(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Tags.RemoveSongTag(c.Request.Context(), id, c.Param("tag"))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// changeSongTags назначает песне теги из тела запроса; при replace = true прежние теги снимаются
+func (h *TagHandler) changeSongTags(c *gin.Context, replace bool) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	var request models.SongTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Printf("ERROR: Invalid song tags data: %v", err)
+		c.String(http.StatusBadRequest, "invalid input: %v", err)
+		return
+	}
+
+	var (
+		song *models.Song
+		err  error
+	)
+	if replace {
+		song, err = h.Tags.SetSongTags(c.Request.Context(), id, request.Tags)
+	} else {
+		song, err = h.Tags.AddSongTags(c.Request.Context(), id, request.Tags)
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.JSON(http.StatusOK, song)
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"music-library/models"
+	"music-library/repository"
+	"net/http"
+)
+
+// GetTrashedSongs возвращает список песен в корзине с пагинацией
+func (h *SongHandler) GetTrashedSongs(c *gin.Context) {
+	pager, paramErr := parsePagination(c, h.Pagination)
+	if paramErr != nil {
+		log.Printf("ERROR: Invalid pagination: %v", paramErr)
+		respondParamError(c, paramErr)
+		return
+	}
+
+	songs, total, err := h.Songs.GetTrashedSongs(c.Request.Context(), pager.Page, pager.Limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SongListResponse{
+		Songs: songs,
+		Total: total,
+		Page:  pager.Page,
+		Limit: pager.Limit,
+	})
+}
+
+// RestoreSong восстанавливает песню из корзины
+func (h *SongHandler) RestoreSong(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	song, err := h.Songs.RestoreSong(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.String(http.StatusNotFound, "song not found in trash")
+	case errors.Is(err, repository.ErrDuplicateSong):
+		c.String(http.StatusConflict, "song with the same group and title already exists")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.JSON(http.StatusOK, song)
+	}
+}
+
+// PurgeSong окончательно удаляет песню из корзины
+func (h *SongHandler) PurgeSong(c *gin.Context) {
+	id, paramErr := parseIDParam(c)
+	if paramErr != nil {
+		respondParamError(c, paramErr)
+		return
+	}
+
+	err := h.Songs.PurgeSong(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.String(http.StatusNotFound, "not found")
+	case errors.Is(err, repository.ErrSongNotTrashed):
+		c.String(http.StatusConflict, "song must be moved to trash before it can be purged")
+	case err != nil:
+		c.String(http.StatusInternalServerError, "internal server error")
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
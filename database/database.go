@@ -1,49 +1,108 @@
 package database
 
 import (
+	"fmt"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"log"
-	"os"
+	"music-library/config"
+	"strings"
 	"sync"
 )
 
+// Поддерживаемые драйверы базы данных (параметр database.driver)
+const (
+	DriverPostgres = "postgres" // PostgreSQL, строка подключения в database.url
+	DriverSQLite   = "sqlite"   // SQLite, путь к файлу в database.sqlite_path (":memory:" — база в памяти)
+)
+
 // db - глобальная переменная для хранения соединения с базой данных.
 var (
-	db   *gorm.DB
-	once sync.Once // Используется для обеспечения одиночного создания подключения
+	db         *gorm.DB
+	connectErr error     // Ошибка первого подключения, возвращается и при повторных вызовах
+	once       sync.Once // Используется для обеспечения одиночного создания подключения
 )
 
-// Connect устанавливает соединение с базой данных PostgreSQL.
+// Connect устанавливает соединение с базой данных, выбранной в конфигурации (по умолчанию PostgreSQL).
 // Используется шаблон Singleton для предотвращения повторных подключений.
 //
-// Возвращает *gorm.DB — активное соединение с базой данных.
-func Connect() *gorm.DB {
+// SQLite предназначена для локальной разработки и тестов: полнотекстовый и нечеткий поиск
+// на ней работают в упрощенном виде (см. SearchRepository и SongRepository.FindSimilarSongs).
+//
+// Аргументы:
+//   - cfg: config.DatabaseConfig — драйвер, параметры подключения и настройки пула (уже проверены config.Load).
+//
+// Возвращает:
+//   - *gorm.DB: активное соединение с базой данных.
+//   - error: ошибка, если драйвер не поддерживается или подключиться не удалось.
+func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	once.Do(func() {
-		// Получаем строку подключения из переменной окружения DATABASE_URL
-		dsn := os.Getenv("DATABASE_URL")
-		if dsn == "" {
-			log.Fatal("ERROR: DATABASE_URL is not set")
+		var dialector gorm.Dialector
+		switch cfg.Driver {
+		case DriverPostgres:
+			dialector = postgres.Open(cfg.URL)
+		case DriverSQLite:
+			dialector = sqlite.Open(sqliteDSN(cfg.SQLitePath))
+		default:
+			connectErr = fmt.Errorf("unsupported database driver %q, expected %s or %s", cfg.Driver, DriverPostgres, DriverSQLite)
+			return
 		}
 
-		// Подключаемся к PostgreSQL через GORM
-		var err error
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		// Подключаемся к базе данных через GORM
+		// TranslateError приводит ошибки нарушения ограничений к gorm.ErrDuplicatedKey и gorm.ErrForeignKeyViolated
+		conn, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
 		if err != nil {
-			log.Fatal("ERROR: Failed to connect to the database:", err)
+			connectErr = fmt.Errorf("failed to connect to the database: %w", err)
+			return
 		}
 
 		// Получаем объект sql.DB для настройки соединений
-		sqlDB, err := db.DB()
+		sqlDB, err := conn.DB()
 		if err != nil {
-			log.Fatal("ERROR: Failed to get sql.DB from gorm.DB:", err)
+			connectErr = fmt.Errorf("failed to get sql.DB from gorm.DB: %w", err)
+			return
 		}
 
 		// Настройка пула подключений
-		sqlDB.SetMaxOpenConns(10)   // Устанавливаем максимум 10 открытых соединений
-		sqlDB.SetMaxIdleConns(5)    // Разрешаем держать до 5 неактивных соединений
-		sqlDB.SetConnMaxLifetime(0) // Соединения не будут закрываться автоматически
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)       // Максимум открытых соединений
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)       // Максимум неактивных соединений
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime) // 0 — соединения не закрываются автоматически
+		if cfg.Driver == DriverSQLite {
+			// SQLite допускает одного писателя, а база в памяти существует только внутри соединения:
+			// единственное соединение не должно закрываться по времени жизни или простоя
+			sqlDB.SetMaxOpenConns(1)
+			sqlDB.SetMaxIdleConns(1)
+			sqlDB.SetConnMaxLifetime(0)
+			sqlDB.SetConnMaxIdleTime(0)
+		}
+		db = conn
+		log.Printf("INFO: Using %s database driver", cfg.Driver)
 	})
 
-	return db
+	return db, connectErr
+}
+
+// Close закрывает пул соединений с базой данных. Вызывается последним при остановке приложения,
+// когда HTTP-сервер и фоновые задачи уже завершены.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close the database: %w", err)
+	}
+	log.Println("INFO: Database connection closed.")
+	return nil
+}
+
+// sqliteDSN дополняет путь к базе SQLite параметрами подключения: включает проверку
+// внешних ключей (без нее не работают ON DELETE CASCADE и SET NULL) и ожидание блокировки.
+func sqliteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "_foreign_keys=on&_busy_timeout=5000"
 }
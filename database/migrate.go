@@ -1,15 +1,373 @@
 package database
 
 import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
-	"music-library/models"
 )
 
-// Migrate выполняет автоматическую миграцию моделей в базе данных.
-// Использует GORM AutoMigrate для создания или обновления таблиц.
+// scripts содержит SQL-скрипты миграций вида <версия>_<название>.up.sql / .down.sql:
+// в каталоге scripts — для PostgreSQL, в scripts/sqlite — те же версии схемы для SQLite.
+//
+//go:embed scripts/*.sql scripts/sqlite/*.sql
+var scripts embed.FS
+
+// migrationsTable — таблица с историей примененных миграций.
+const migrationsTable = "schema_migrations"
+
+// migrationLockID — ключ advisory-блокировки PostgreSQL, не дающий нескольким
+// экземплярам приложения применять миграции одновременно.
+const migrationLockID = 724031
+
+// ErrDatabaseAhead возвращается, если в базе применены миграции, неизвестные текущей сборке.
+var ErrDatabaseAhead = errors.New("database schema is newer than this binary")
+
+// ErrChecksumMismatch возвращается, если скрипт уже примененной миграции был изменен.
+var ErrChecksumMismatch = errors.New("applied migration checksum mismatch")
+
+// migrationFilePattern разбирает имя файла миграции.
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration — одна версия схемы базы данных.
+type Migration struct {
+	Version  int    // Номер версии
+	Name     string // Название миграции
+	Up       string // SQL для применения
+	Down     string // SQL для отката
+	Checksum string // SHA-256 скрипта применения
+
+	// AfterUp — шаг на Go, который выполняется после скрипта применения в той же транзакции
+	// (nil, если миграция описана только SQL). В контрольную сумму не входит.
+	AfterUp func(tx *gorm.DB) error
+}
+
+// AppliedMigration — запись в таблице schema_migrations.
+type AppliedMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	Checksum  string    `gorm:"size:64;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName задает имя таблицы для AppliedMigration.
+func (AppliedMigration) TableName() string {
+	return migrationsTable
+}
+
+// MigrationStatus описывает состояние одной миграции.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // Скрипт изменился после применения
+	Unknown   bool // Миграция применена в базе, но отсутствует в сборке
+}
+
+// Migrator применяет и откатывает версионированные SQL-миграции.
+//
+// База, созданная до появления мигратора (скриптом init-db/init.sql или AutoMigrate),
+// принимается командой "migrate up" без ручной подготовки: миграция 1 ничего не делает,
+// а миграция 2 создает таблицу songs и индекс только при их отсутствии.
+type Migrator struct {
+	DB         *gorm.DB
+	Migrations []Migration // Миграции, упорядоченные по версии
+}
+
+// NewMigrator создает мигратор со встроенными в сборку скриптами из database/scripts
+// (database/scripts/sqlite для SQLite).
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	dir := "scripts"
+	if db.Dialector.Name() == DriverSQLite {
+		dir = "scripts/sqlite"
+	}
+	migrations, err := LoadMigrations(scripts, dir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range migrations {
+		migrations[i].AfterUp = goSteps[dir][migrations[i].Version]
+	}
+	return &Migrator{DB: db, Migrations: migrations}, nil
+}
+
+// LoadMigrations читает скрипты миграций из каталога dir файловой системы fsys.
+// Для каждой версии обязательны оба скрипта: up и down.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down scripts", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// WithContext возвращает копию мигратора, запросы которого выполняются с контекстом ctx.
+func (m *Migrator) WithContext(ctx context.Context) *Migrator {
+	copied := *m
+	copied.DB = m.DB.WithContext(ctx)
+	return &copied
+}
+
+// Latest возвращает номер последней версии, известной сборке.
+func (m *Migrator) Latest() int {
+	if len(m.Migrations) == 0 {
+		return 0
+	}
+	return m.Migrations[len(m.Migrations)-1].Version
+}
+
+// ensureTable создает таблицу schema_migrations, если ее еще нет.
+func (m *Migrator) ensureTable() error {
+	// Драйвер SQLite читает как время только колонки типа DATETIME
+	timestampType := "TIMESTAMP WITH TIME ZONE"
+	if m.DB.Dialector.Name() == DriverSQLite {
+		timestampType = "DATETIME"
+	}
+	return m.DB.Exec(`CREATE TABLE IF NOT EXISTS ` + migrationsTable + ` (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum VARCHAR(64) NOT NULL,
+		applied_at ` + timestampType + ` NOT NULL
+	)`).Error
+}
+
+// applied возвращает примененные миграции, упорядоченные по версии.
+func (m *Migrator) applied(db *gorm.DB) ([]AppliedMigration, error) {
+	var applied []AppliedMigration
+	if err := db.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", migrationsTable, err)
+	}
+	return applied, nil
+}
+
+// Current возвращает номер последней примененной миграции (0, если миграций не было).
+func (m *Migrator) Current() (int, error) {
+	if err := m.ensureTable(); err != nil {
+		return 0, err
+	}
+	applied, err := m.applied(m.DB)
+	if err != nil || len(applied) == 0 {
+		return 0, err
+	}
+	return applied[len(applied)-1].Version, nil
+}
+
+// Check проверяет, что схема базы совместима со сборкой: в базе нет миграций новее
+// известных сборке и скрипты примененных миграций не изменялись.
+func (m *Migrator) Check() error {
+	if err := m.ensureTable(); err != nil {
+		return err
+	}
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		switch {
+		case status.Unknown && status.Version > m.Latest():
+			return fmt.Errorf("%w: database is at version %d, binary knows up to %d", ErrDatabaseAhead, status.Version, m.Latest())
+		case status.Unknown:
+			return fmt.Errorf("migration %d is applied in the database but missing from this binary", status.Version)
+		case status.Modified:
+			return fmt.Errorf("%w: migration %d_%s", ErrChecksumMismatch, status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+// Status возвращает состояние всех миграций: известных сборке и примененных в базе.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(m.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedByVersion := make(map[int]AppliedMigration, len(applied))
+	for _, record := range applied {
+		appliedByVersion[record.Version] = record
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.Migrations))
+	for _, migration := range m.Migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := appliedByVersion[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			status.Modified = record.Checksum != migration.Checksum
+			delete(appliedByVersion, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range appliedByVersion {
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Up применяет все еще не примененные миграции.
+func (m *Migrator) Up() error {
+	return m.To(m.Latest())
+}
+
+// Down откатывает последнюю примененную миграцию.
+func (m *Migrator) Down() error {
+	current, err := m.Current()
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		log.Println("INFO: No migrations to roll back")
+		return nil
+	}
+
+	target := 0
+	for _, migration := range m.Migrations {
+		if migration.Version < current {
+			target = migration.Version
+		}
+	}
+	return m.To(target)
+}
+
+// To приводит схему к указанной версии: применяет миграции с версией <= target
+// и откатывает (в обратном порядке) примененные миграции с версией > target.
+func (m *Migrator) To(target int) error {
+	if err := m.Check(); err != nil {
+		return err
+	}
+	if target < 0 || target > m.Latest() {
+		return fmt.Errorf("unknown target version %d, available versions: 0..%d", target, m.Latest())
+	}
+
+	// Откат миграций новее целевой версии
+	for i := len(m.Migrations) - 1; i >= 0; i-- {
+		if migration := m.Migrations[i]; migration.Version > target {
+			if err := m.step(migration, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Применение миграций до целевой версии
+	for _, migration := range m.Migrations {
+		if migration.Version <= target {
+			if err := m.step(migration, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// step применяет (up = true) или откатывает миграцию в отдельной транзакции.
+// Уже примененные миграции не применяются повторно, а неприменные — не откатываются.
+func (m *Migrator) step(migration Migration, up bool) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == DriverPostgres {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+				return fmt.Errorf("failed to acquire migration lock: %w", err)
+			}
+		}
+
+		var count int64
+		if err := tx.Model(&AppliedMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		isApplied := count > 0
+		if isApplied == up {
+			return nil
+		}
+
+		if up {
+			log.Printf("INFO: Applying migration %d_%s", migration.Version, migration.Name)
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			if migration.AfterUp != nil {
+				if err := migration.AfterUp(tx); err != nil {
+					return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+				}
+			}
+			return tx.Create(&AppliedMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				AppliedAt: time.Now(),
+			}).Error
+		}
+
+		log.Printf("INFO: Rolling back migration %d_%s", migration.Version, migration.Name)
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return fmt.Errorf("rollback of migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		return tx.Where("version = ?", migration.Version).Delete(&AppliedMigration{}).Error
+	})
+}
+
+// Migrate приводит схему базы данных к последней версии, известной сборке.
+// Возвращает ошибку, если база новее сборки или скрипты примененных миграций были изменены.
 //
 // Аргументы:
 //   - db: *gorm.DB — соединение с базой данных.
@@ -21,18 +379,17 @@ func Migrate(db *gorm.DB) error {
 		return fmt.Errorf("ERROR: database connection is nil")
 	}
 
-	// Определяем список моделей для миграции
-	modelsToMigrate := []interface{}{
-		&models.Song{}, // Таблица для хранения информации о песнях
-		// Добавьте другие модели сюда при необходимости
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
 	}
 
 	// Выполняем миграцию
-	if err := db.AutoMigrate(modelsToMigrate...); err != nil {
+	if err := migrator.Up(); err != nil {
 		log.Printf("ERROR: Migration failed: %v", err)
 		return err
 	}
 
-	log.Println("INFO: Migration completed successfully")
+	log.Printf("INFO: Migration completed successfully, schema version: %d", migrator.Latest())
 	return nil
 }
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+
+	"gorm.io/gorm"
+	"music-library/normalize"
+)
+
+// goSteps — шаги миграций на Go для вычислений, которые нельзя выразить на SQL диалекта.
+// Ключи — каталог скриптов (см. NewMigrator) и версия миграции.
+var goSteps = map[string]map[int]func(tx *gorm.DB) error{
+	"scripts": {
+		11: foldArtistNameKeys,
+	},
+	"scripts/sqlite": {
+		// В SQLite нет unaccent и регулярных выражений: скрипт 9 заполняет ключи
+		// приближенно, а точные значения вычисляются до объединения дубликатов в миграции 10
+		9:  backfillSongKeys,
+		11: foldArtistNameKeys,
+	},
+}
+
+// songKeysBatchSize — число песен, ключи которых пересчитываются за один запрос выборки.
+const songKeysBatchSize = 500
+
+// songNames — название группы и песни, по которым вычисляются ключи.
+type songNames struct {
+	ID    int
+	Group string
+	Song  string
+}
+
+// backfillSongKeys заполняет group_key и song_key всех песен (включая песни в корзине)
+// значениями normalize.Fold, которые приложение использует для поиска и проверки дубликатов.
+func backfillSongKeys(tx *gorm.DB) error {
+	var batch []songNames
+	result := tx.Table("songs").Select(`id, "group", song`).FindInBatches(&batch, songKeysBatchSize, func(_ *gorm.DB, _ int) error {
+		for _, names := range batch {
+			err := tx.Exec("UPDATE songs SET group_key = ?, song_key = ? WHERE id = ?",
+				normalize.Fold(names.Group), normalize.Fold(names.Song), names.ID).Error
+			if err != nil {
+				return fmt.Errorf("failed to update keys of song %d: %w", names.ID, err)
+			}
+		}
+		return nil
+	})
+	return result.Error
+}
+
+// artistRecord — поля исполнителя, которые учитываются при объединении.
+type artistRecord struct {
+	ID         int
+	Name       string
+	NameKey    string
+	Country    string
+	FormedYear *int
+	Aliases    []string `gorm:"serializer:json"`
+}
+
+// foldArtistNameKeys пересчитывает name_key исполнителей функцией normalize.Fold.
+// Исполнители с совпавшими ключами объединяются: остается исполнитель с наименьшим ID,
+// имена и псевдонимы остальных становятся его псевдонимами, пустые страна и год образования
+// заполняются, а песни и альбомы переносятся на него.
+func foldArtistNameKeys(tx *gorm.DB) error {
+	var artists []artistRecord
+	if err := tx.Table("artists").Order("id").Find(&artists).Error; err != nil {
+		return fmt.Errorf("failed to load artists: %w", err)
+	}
+
+	kept := make(map[string]*artistRecord, len(artists))
+	var order []*artistRecord
+	for i := range artists {
+		artist := &artists[i]
+		key := normalize.Fold(artist.Name)
+		main, ok := kept[key]
+		if !ok {
+			kept[key] = artist
+			order = append(order, artist)
+			continue
+		}
+		if err := mergeArtist(tx, main, artist); err != nil {
+			return err
+		}
+	}
+
+	// Дубликаты уже удалены, поэтому новые ключи не нарушают уникальный индекс
+	for _, artist := range order {
+		if artist.Aliases == nil {
+			artist.Aliases = []string{}
+		}
+		aliases, err := json.Marshal(artist.Aliases)
+		if err != nil {
+			return err
+		}
+		err = tx.Exec("UPDATE artists SET name_key = ?, country = ?, formed_year = ?, aliases = ? WHERE id = ?",
+			normalize.Fold(artist.Name), artist.Country, artist.FormedYear, string(aliases), artist.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed to update artist %d: %w", artist.ID, err)
+		}
+	}
+	return nil
+}
+
+// mergeArtist переносит данные, песни и альбомы исполнителя duplicate на исполнителя main
+// и удаляет duplicate.
+func mergeArtist(tx *gorm.DB, main, duplicate *artistRecord) error {
+	log.Printf("INFO: Merging artist %d '%s' into artist %d '%s'", duplicate.ID, duplicate.Name, main.ID, main.Name)
+	for _, alias := range append([]string{duplicate.Name}, duplicate.Aliases...) {
+		if alias != main.Name && !slices.Contains(main.Aliases, alias) {
+			main.Aliases = append(main.Aliases, alias)
+		}
+	}
+	if main.Country == "" {
+		main.Country = duplicate.Country
+	}
+	if main.FormedYear == nil {
+		main.FormedYear = duplicate.FormedYear
+	}
+
+	for _, table := range []string{"songs", "albums"} {
+		if err := tx.Exec("UPDATE "+table+" SET artist_id = ? WHERE artist_id = ?", main.ID, duplicate.ID).Error; err != nil {
+			return fmt.Errorf("failed to move %s of artist %d: %w", table, duplicate.ID, err)
+		}
+	}
+	if err := tx.Exec("DELETE FROM artists WHERE id = ?", duplicate.ID).Error; err != nil {
+		return fmt.Errorf("failed to delete merged artist %d: %w", duplicate.ID, err)
+	}
+	return nil
+}
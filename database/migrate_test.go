@@ -0,0 +1,178 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openSQLite открывает пустую базу SQLite в памяти с одним соединением, как это делает Connect.
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(":memory:")), &gorm.Config{TranslateError: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+// newSQLiteMigrator создает мигратор для новой базы в памяти.
+func newSQLiteMigrator(t *testing.T) *Migrator {
+	t.Helper()
+	migrator, err := NewMigrator(openSQLite(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator
+}
+
+func TestMigratorUpDown(t *testing.T) {
+	migrator := newSQLiteMigrator(t)
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if current, _ := migrator.Current(); current != migrator.Latest() {
+		t.Fatalf("Current() = %d, want %d", current, migrator.Latest())
+	}
+	if err := migrator.To(0); err != nil {
+		t.Fatalf("To(0) error = %v", err)
+	}
+	if current, _ := migrator.Current(); current != 0 {
+		t.Fatalf("Current() after To(0) = %d, want 0", current)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() after rollback error = %v", err)
+	}
+}
+
+func TestSQLiteSongKeysUseFold(t *testing.T) {
+	migrator := newSQLiteMigrator(t)
+	if err := migrator.To(8); err != nil {
+		t.Fatalf("To(8) error = %v", err)
+	}
+
+	// Названия отличаются регистром, пробелами внутри строки и диакритикой:
+	// после миграций 9 и 10 остается одна активная песня
+	for _, names := range [][2]string{{"Beyoncé", "Halo"}, {"  beyonce ", "HALO"}, {"Sigur  Rós", "Hoppípolla"}} {
+		err := migrator.DB.Exec(`INSERT INTO songs ("group", song, release_date) VALUES (?, ?, '2008-01-01')`, names[0], names[1]).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	var keys []struct {
+		GroupKey string
+		SongKey  string
+	}
+	if err := migrator.DB.Table("songs").Where("deleted_at IS NULL").Order("id").Find(&keys).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"beyonce", "halo"}, {"sigur ros", "hoppipolla"}}
+	if len(keys) != len(want) {
+		t.Fatalf("active songs = %+v, want keys %v", keys, want)
+	}
+	for i, key := range keys {
+		if key.GroupKey != want[i][0] || key.SongKey != want[i][1] {
+			t.Errorf("song %d keys = %q/%q, want %q/%q", i, key.GroupKey, key.SongKey, want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestFoldArtistNameKeysMergesArtists(t *testing.T) {
+	migrator := newSQLiteMigrator(t)
+	if err := migrator.To(10); err != nil {
+		t.Fatalf("To(10) error = %v", err)
+	}
+
+	// До миграции 11 ключи учитывали только регистр и пробелы: "Beyoncé" и "Beyonce" — разные исполнители
+	statements := []string{
+		`INSERT INTO artists (id, name, name_key, country, aliases) VALUES (1, 'Beyoncé', 'beyoncé', '', '["Queen B"]')`,
+		`INSERT INTO artists (id, name, name_key, country, formed_year, aliases) VALUES (2, 'Beyonce', 'beyonce', 'US', 1997, '[]')`,
+		`INSERT INTO artists (id, name, name_key, aliases) VALUES (3, 'Sigur Rós', 'sigur rós', '[]')`,
+		`INSERT INTO songs ("group", song, release_date, artist_id, group_key, song_key) VALUES ('Beyonce', 'Halo', '2008-01-01', 2, 'beyonce', 'halo')`,
+		`INSERT INTO albums (title, artist_id) VALUES ('I Am... Sasha Fierce', 2)`,
+	}
+	for _, statement := range statements {
+		if err := migrator.DB.Exec(statement).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	var artists []artistRecord
+	if err := migrator.DB.Table("artists").Order("id").Find(&artists).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(artists) != 2 {
+		t.Fatalf("artists = %+v, want 2 after merge", artists)
+	}
+	beyonce, sigurRos := artists[0], artists[1]
+	if beyonce.ID != 1 || beyonce.NameKey != "beyonce" || beyonce.Country != "US" || beyonce.FormedYear == nil || *beyonce.FormedYear != 1997 {
+		t.Errorf("merged artist = %+v", beyonce)
+	}
+	if strings.Join(beyonce.Aliases, "|") != "Queen B|Beyonce" {
+		t.Errorf("aliases = %q, want [Queen B Beyonce]", beyonce.Aliases)
+	}
+	if sigurRos.NameKey != "sigur ros" {
+		t.Errorf("name_key = %q, want %q", sigurRos.NameKey, "sigur ros")
+	}
+
+	for _, table := range []string{"songs", "albums"} {
+		var artistID int
+		if err := migrator.DB.Table(table).Select("artist_id").Scan(&artistID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if artistID != 1 {
+			t.Errorf("%s.artist_id = %d, want 1", table, artistID)
+		}
+	}
+
+	if err := migrator.Down(); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+}
+
+func TestMigratorAdoptsDatabaseWithoutHistory(t *testing.T) {
+	migrator := newSQLiteMigrator(t)
+	// Таблица из прежнего init-db/init.sql, созданная до появления мигратора
+	err := migrator.DB.Exec(`CREATE TABLE songs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		"group" VARCHAR(255) NOT NULL,
+		song VARCHAR(255) NOT NULL,
+		release_date DATE,
+		text TEXT,
+		link VARCHAR(2083)
+	)`).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.DB.Exec(`INSERT INTO songs ("group", song) VALUES ('Muse', 'Uprising')`).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	var count int64
+	if err := migrator.DB.Table("songs").Where("group_key = ? AND artist_id IS NOT NULL", "muse").Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("adopted songs = %d, want 1", count)
+	}
+}
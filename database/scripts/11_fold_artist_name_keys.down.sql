@@ -0,0 +1,5 @@
+-- Ключи возвращаются к прежней нормализации (регистр и пробелы, см. normalize.Name).
+-- Ключи оставшихся исполнителей различаются и после этого; объединенные исполнители
+-- не разделяются обратно.
+UPDATE artists
+SET name_key = lower(regexp_replace(btrim(name), '\s+', ' ', 'g'));
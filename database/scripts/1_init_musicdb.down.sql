@@ -1 +1,2 @@
-DROP DATABASE IF EXISTS musicdb;
\ No newline at end of file
+-- База данных не удаляется миграциями: это делается вручную вне приложения.
+SELECT 1;
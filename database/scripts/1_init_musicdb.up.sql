@@ -1 +1,4 @@
-CREATE DATABASE musicdb;
\ No newline at end of file
+-- База данных musicdb создается до запуска миграций (см. init-db/init.sql),
+-- поскольку миграции выполняются внутри уже подключенной базы.
+-- Миграция сохранена, чтобы не менять нумерацию версий.
+SELECT 1;
@@ -1,4 +1,4 @@
-CREATE TABLE songs (
+CREATE TABLE IF NOT EXISTS songs (
                        id SERIAL PRIMARY KEY,          -- Уникальный идентификатор песни
                        "group" VARCHAR(255) NOT NULL,  -- Название группы
                        song VARCHAR(255) NOT NULL,     -- Название песни
@@ -8,4 +8,4 @@ CREATE TABLE songs (
 );
 
 -- Добавляем индекс для быстрого поиска по группе и названию песни
-CREATE INDEX idx_group_song ON songs ("group", song);
+CREATE INDEX IF NOT EXISTS idx_group_song ON songs ("group", song);
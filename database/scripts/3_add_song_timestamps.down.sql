@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_songs_deleted_at;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE songs DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE songs DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,7 @@
+-- Служебные колонки модели Song: время создания, изменения и мягкого удаления
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Индекс для отбора активных песен и содержимого корзины
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs (deleted_at);
@@ -0,0 +1,52 @@
+-- Исполнители: вместо свободного текста в songs."group" песни ссылаются на запись в artists
+CREATE TABLE IF NOT EXISTS artists (
+                         id SERIAL PRIMARY KEY,                      -- Уникальный идентификатор исполнителя
+                         name VARCHAR(255) NOT NULL,                 -- Имя исполнителя
+                         name_key VARCHAR(255) NOT NULL,             -- Нормализованное имя для поиска дубликатов
+                         sort_name VARCHAR(255) NOT NULL DEFAULT '', -- Имя для сортировки
+                         country VARCHAR(100) NOT NULL DEFAULT '',   -- Страна
+                         formed_year INTEGER,                        -- Год образования
+                         aliases TEXT NOT NULL DEFAULT '[]',         -- Псевдонимы (JSON-массив строк)
+                         created_at TIMESTAMP WITH TIME ZONE,
+                         updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artists_name_key ON artists (name_key);
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS artist_id INTEGER REFERENCES artists (id);
+CREATE INDEX IF NOT EXISTS idx_songs_artist_id ON songs (artist_id);
+
+-- Перенос существующих групп: варианты написания, отличающиеся регистром и пробелами,
+-- объединяются в одного исполнителя. Основным именем становится самый частый вариант,
+-- остальные сохраняются как псевдонимы.
+WITH variants AS (
+    SELECT lower(regexp_replace(btrim("group"), '\s+', ' ', 'g')) AS name_key,
+           regexp_replace(btrim("group"), '\s+', ' ', 'g')        AS variant,
+           count(*)                                                AS songs
+    FROM songs
+    WHERE btrim("group") <> ''
+    GROUP BY 1, 2
+), ranked AS (
+    SELECT name_key, variant,
+           row_number() OVER (PARTITION BY name_key ORDER BY songs DESC, variant) AS rank
+    FROM variants
+)
+INSERT INTO artists (name, name_key, sort_name, aliases, created_at, updated_at)
+SELECT main.variant,
+       main.name_key,
+       main.variant,
+       COALESCE((SELECT json_agg(alias.variant ORDER BY alias.variant)::text
+                 FROM ranked alias
+                 WHERE alias.name_key = main.name_key AND alias.rank > 1), '[]'),
+       now(),
+       now()
+FROM ranked main
+WHERE main.rank = 1
+ON CONFLICT (name_key) DO NOTHING;
+
+-- Песни ссылаются на исполнителя, а название группы приводится к основному имени
+UPDATE songs
+SET artist_id = artists.id,
+    "group"   = artists.name
+FROM artists
+WHERE artists.name_key = lower(regexp_replace(btrim(songs."group"), '\s+', ' ', 'g'));
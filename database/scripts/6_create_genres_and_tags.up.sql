@@ -0,0 +1,37 @@
+-- Иерархические жанры и пользовательские теги песен
+CREATE TABLE IF NOT EXISTS genres (
+                        id SERIAL PRIMARY KEY,                                   -- Уникальный идентификатор жанра
+                        name VARCHAR(100) NOT NULL,                              -- Название жанра
+                        name_key VARCHAR(100) NOT NULL,                          -- Нормализованное название
+                        parent_id INTEGER REFERENCES genres (id),                -- Родительский жанр
+                        created_at TIMESTAMP WITH TIME ZONE,
+                        updated_at TIMESTAMP WITH TIME ZONE,
+                        CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_genres_name_key ON genres (name_key);
+CREATE INDEX IF NOT EXISTS idx_genres_parent_id ON genres (parent_id);
+
+CREATE TABLE IF NOT EXISTS tags (
+                        id SERIAL PRIMARY KEY,                                   -- Уникальный идентификатор тега
+                        name VARCHAR(64) NOT NULL,                               -- Нормализованное имя тега
+                        created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags (name);
+
+CREATE TABLE IF NOT EXISTS song_genres (
+                        song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                        genre_id INTEGER NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+                        PRIMARY KEY (song_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_genres_genre_id ON song_genres (genre_id);
+
+CREATE TABLE IF NOT EXISTS song_tags (
+                        song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                        tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+                        PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_tags_tag_id ON song_tags (tag_id);
@@ -0,0 +1,27 @@
+-- Пользовательские плейлисты с упорядоченными записями
+CREATE TABLE IF NOT EXISTS playlists (
+                        id SERIAL PRIMARY KEY,                                    -- Уникальный идентификатор плейлиста
+                        owner VARCHAR(255) NOT NULL,                              -- Владелец
+                        name VARCHAR(255) NOT NULL,                               -- Название
+                        description TEXT NOT NULL DEFAULT '',                     -- Описание
+                        visibility VARCHAR(16) NOT NULL DEFAULT 'private'
+                            CHECK (visibility IN ('private', 'public')),          -- Видимость
+                        created_at TIMESTAMP WITH TIME ZONE,
+                        updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlists_owner ON playlists (owner);
+CREATE INDEX IF NOT EXISTS idx_playlists_visibility ON playlists (visibility);
+
+-- Записи плейлиста. Проверка уникальности позиции отложена до конца транзакции,
+-- чтобы позиции можно было сдвигать одним UPDATE.
+CREATE TABLE IF NOT EXISTS playlist_entries (
+                        id SERIAL PRIMARY KEY,                                    -- Стабильный идентификатор записи
+                        playlist_id INTEGER NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+                        song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                        position INTEGER NOT NULL CHECK (position > 0),           -- Позиция в плейлисте (с 1)
+                        added_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                        CONSTRAINT uq_playlist_entries_position UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_entries_song_id ON playlist_entries (song_id);
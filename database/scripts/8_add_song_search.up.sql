@@ -0,0 +1,11 @@
+-- Полнотекстовый поиск по группе, названию и тексту песни.
+-- Конфигурация поиска (язык) задается для каждой песни: по умолчанию simple — без стемминга.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_language REGCONFIG NOT NULL DEFAULT 'simple';
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector(search_language, coalesce("group", '')), 'A') ||
+    setweight(to_tsvector(search_language, coalesce(song, '')), 'A') ||
+    setweight(to_tsvector(search_language, coalesce(text, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_songs_name_trgm;
+DROP INDEX IF EXISTS idx_songs_group_song_key;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS song_key;
+ALTER TABLE songs DROP COLUMN IF EXISTS group_key;
+
+-- Расширения unaccent и pg_trgm не удаляются: они могут использоваться вне приложения
@@ -0,0 +1,15 @@
+-- Нормализованные названия группы и песни для поиска без учета регистра, пробелов
+-- и диакритических знаков, а также индекс триграмм для нечеткого поиска.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS group_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS song_key VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Ключи новых и измененных песен заполняет приложение (normalize.Fold)
+UPDATE songs
+SET group_key = lower(regexp_replace(btrim(unaccent("group")), '\s+', ' ', 'g')),
+    song_key  = lower(regexp_replace(btrim(unaccent(song)), '\s+', ' ', 'g'));
+
+CREATE INDEX IF NOT EXISTS idx_songs_group_song_key ON songs (group_key, song_key);
+CREATE INDEX IF NOT EXISTS idx_songs_name_trgm ON songs USING GIST ((group_key || ' ' || song_key) gist_trgm_ops);
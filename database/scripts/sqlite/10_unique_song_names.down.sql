@@ -0,0 +1,3 @@
+-- Объединенные дубликаты остаются в корзине и могут быть восстановлены вручную
+DROP INDEX IF EXISTS idx_songs_group_song_key_active;
+CREATE INDEX IF NOT EXISTS idx_songs_group_song_key ON songs (group_key, song_key);
@@ -0,0 +1,34 @@
+-- Уникальность активных песен по нормализованной паре группа+песня.
+-- Перед созданием индекса дубликаты объединяются: остается песня с наименьшим ID,
+-- ее записи в плейлистах, жанры и теги переносятся, а остальные песни перемещаются в корзину.
+CREATE TEMPORARY TABLE song_duplicates AS
+SELECT id, keep_id
+FROM (
+    SELECT id, min(id) OVER (PARTITION BY group_key, song_key) AS keep_id
+    FROM songs
+    WHERE deleted_at IS NULL
+) ranked
+WHERE id <> keep_id;
+
+UPDATE playlist_entries
+SET song_id = d.keep_id
+FROM song_duplicates d
+WHERE playlist_entries.song_id = d.id;
+
+INSERT OR IGNORE INTO song_genres (song_id, genre_id)
+SELECT d.keep_id, sg.genre_id
+FROM song_genres sg JOIN song_duplicates d ON d.id = sg.song_id;
+
+INSERT OR IGNORE INTO song_tags (song_id, tag_id)
+SELECT d.keep_id, st.tag_id
+FROM song_tags st JOIN song_duplicates d ON d.id = st.song_id;
+
+UPDATE songs
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id IN (SELECT id FROM song_duplicates);
+
+DROP TABLE song_duplicates;
+
+-- Неуникальный индекс из миграции 9 заменяется уникальным частичным
+DROP INDEX IF EXISTS idx_songs_group_song_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_group_song_key_active ON songs (group_key, song_key) WHERE deleted_at IS NULL;
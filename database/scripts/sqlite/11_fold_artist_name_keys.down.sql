@@ -0,0 +1,5 @@
+-- Ключи возвращаются к прежней нормализации (регистр и пробелы, см. normalize.Name).
+-- В SQLite нет регулярных выражений, поэтому, как и в миграции 4, пробелы внутри имени
+-- не схлопываются. Объединенные исполнители не разделяются обратно.
+UPDATE artists
+SET name_key = lower(trim(name));
@@ -0,0 +1,4 @@
+-- Ключи исполнителей приводятся к normalize.Fold, как ключи групп у песен
+-- (без учета диакритических знаков). Пересчет и объединение исполнителей, ключи которых
+-- после этого совпали, выполняет шаг миграции на Go (см. database/migrate_steps.go).
+SELECT 1;
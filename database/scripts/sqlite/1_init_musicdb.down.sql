@@ -0,0 +1,2 @@
+-- Файл базы данных не удаляется миграциями: это делается вручную вне приложения.
+SELECT 1;
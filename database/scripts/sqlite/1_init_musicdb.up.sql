@@ -0,0 +1,3 @@
+-- Файл базы данных SQLite создается при подключении.
+-- Миграция сохранена, чтобы версии совпадали со скриптами PostgreSQL.
+SELECT 1;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_songs_deleted_at;
+
+ALTER TABLE songs DROP COLUMN deleted_at;
+ALTER TABLE songs DROP COLUMN updated_at;
+ALTER TABLE songs DROP COLUMN created_at;
@@ -0,0 +1,8 @@
+-- Служебные колонки модели Song: время создания, изменения и мягкого удаления.
+-- Тип DATETIME нужен драйверу SQLite, чтобы читать значения как время.
+ALTER TABLE songs ADD COLUMN created_at DATETIME;
+ALTER TABLE songs ADD COLUMN updated_at DATETIME;
+ALTER TABLE songs ADD COLUMN deleted_at DATETIME;
+
+-- Индекс для отбора активных песен и содержимого корзины
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs (deleted_at);
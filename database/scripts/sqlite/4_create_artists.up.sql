@@ -0,0 +1,33 @@
+-- Исполнители: вместо свободного текста в songs."group" песни ссылаются на запись в artists
+CREATE TABLE IF NOT EXISTS artists (
+                         id INTEGER PRIMARY KEY AUTOINCREMENT,       -- Уникальный идентификатор исполнителя
+                         name VARCHAR(255) NOT NULL,                 -- Имя исполнителя
+                         name_key VARCHAR(255) NOT NULL,             -- Нормализованное имя для поиска дубликатов
+                         sort_name VARCHAR(255) NOT NULL DEFAULT '', -- Имя для сортировки
+                         country VARCHAR(100) NOT NULL DEFAULT '',   -- Страна
+                         formed_year INTEGER,                        -- Год образования
+                         aliases TEXT NOT NULL DEFAULT '[]',         -- Псевдонимы (JSON-массив строк)
+                         created_at DATETIME,
+                         updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artists_name_key ON artists (name_key);
+
+ALTER TABLE songs ADD COLUMN artist_id INTEGER REFERENCES artists (id);
+CREATE INDEX IF NOT EXISTS idx_songs_artist_id ON songs (artist_id);
+
+-- Перенос существующих групп. В SQLite нет регулярных выражений, поэтому варианты написания
+-- объединяются только по регистру и крайним пробелам; псевдонимы не заполняются.
+INSERT INTO artists (name, name_key, sort_name, created_at, updated_at)
+SELECT min(trim("group")), lower(trim("group")), min(trim("group")), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM songs
+WHERE trim("group") <> ''
+GROUP BY lower(trim("group"))
+ON CONFLICT (name_key) DO NOTHING;
+
+-- Песни ссылаются на исполнителя, а название группы приводится к основному имени
+UPDATE songs
+SET artist_id = artists.id,
+    "group"   = artists.name
+FROM artists
+WHERE artists.name_key = lower(trim(songs."group"));
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_songs_album_position;
+
+ALTER TABLE songs DROP COLUMN disc_number;
+ALTER TABLE songs DROP COLUMN track_number;
+ALTER TABLE songs DROP COLUMN album_id;
+
+DROP TABLE IF EXISTS albums;
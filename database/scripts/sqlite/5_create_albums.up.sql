@@ -0,0 +1,22 @@
+-- Альбомы и положение песен в них (номер диска и трека)
+CREATE TABLE IF NOT EXISTS albums (
+                        id INTEGER PRIMARY KEY AUTOINCREMENT,                 -- Уникальный идентификатор альбома
+                        title VARCHAR(255) NOT NULL,                          -- Название альбома
+                        artist_id INTEGER REFERENCES artists (id),            -- Исполнитель
+                        release_date DATE,                                    -- Дата релиза
+                        cover_link VARCHAR(2083) NOT NULL DEFAULT '',         -- Ссылка на обложку
+                        total_tracks INTEGER CHECK (total_tracks > 0),        -- Общее количество треков
+                        created_at DATETIME,
+                        updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_albums_artist_id ON albums (artist_id);
+
+ALTER TABLE songs ADD COLUMN album_id INTEGER REFERENCES albums (id) ON DELETE SET NULL;
+ALTER TABLE songs ADD COLUMN track_number INTEGER CHECK (track_number > 0);
+ALTER TABLE songs ADD COLUMN disc_number INTEGER CHECK (disc_number > 0);
+
+-- На одном диске альбома не может быть двух активных песен с одинаковым номером трека
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_album_position
+    ON songs (album_id, disc_number, track_number)
+    WHERE album_id IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,28 @@
+-- Пользовательские плейлисты с упорядоченными записями
+CREATE TABLE IF NOT EXISTS playlists (
+                        id INTEGER PRIMARY KEY AUTOINCREMENT,                     -- Уникальный идентификатор плейлиста
+                        owner VARCHAR(255) NOT NULL,                              -- Владелец
+                        name VARCHAR(255) NOT NULL,                               -- Название
+                        description TEXT NOT NULL DEFAULT '',                     -- Описание
+                        visibility VARCHAR(16) NOT NULL DEFAULT 'private'
+                            CHECK (visibility IN ('private', 'public')),          -- Видимость
+                        created_at DATETIME,
+                        updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlists_owner ON playlists (owner);
+CREATE INDEX IF NOT EXISTS idx_playlists_visibility ON playlists (visibility);
+
+-- Записи плейлиста. SQLite не умеет откладывать проверку уникальности до конца транзакции,
+-- а позиции сдвигаются одним UPDATE, поэтому индекс по позиции не уникальный:
+-- непрерывность позиций обеспечивает PlaylistRepository.
+CREATE TABLE IF NOT EXISTS playlist_entries (
+                        id INTEGER PRIMARY KEY AUTOINCREMENT,                     -- Стабильный идентификатор записи
+                        playlist_id INTEGER NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+                        song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+                        position INTEGER NOT NULL CHECK (position > 0),           -- Позиция в плейлисте (с 1)
+                        added_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_entries_position ON playlist_entries (playlist_id, position);
+CREATE INDEX IF NOT EXISTS idx_playlist_entries_song_id ON playlist_entries (song_id);
@@ -0,0 +1,3 @@
+-- В SQLite нет полнотекстового поиска PostgreSQL: язык сохраняется, чтобы API работало так же,
+-- а поиск выполняется по подстрокам без стемминга (см. SearchRepository).
+ALTER TABLE songs ADD COLUMN search_language VARCHAR(64) NOT NULL DEFAULT 'simple';
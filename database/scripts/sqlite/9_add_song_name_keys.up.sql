@@ -0,0 +1,12 @@
+-- Нормализованные названия группы и песни. Индекса триграмм в SQLite нет:
+-- сходство названий вычисляет приложение (normalize.Similarity).
+ALTER TABLE songs ADD COLUMN group_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE songs ADD COLUMN song_key VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Ключи новых и измененных песен заполняет приложение (normalize.Fold);
+-- существующие песни получают приближенные ключи без удаления диакритики
+UPDATE songs
+SET group_key = lower(trim("group")),
+    song_key  = lower(trim(song));
+
+CREATE INDEX IF NOT EXISTS idx_songs_group_song_key ON songs (group_key, song_key);
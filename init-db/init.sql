@@ -1,11 +1,5 @@
-CREATE TABLE songs (
-                       id SERIAL PRIMARY KEY,          -- Уникальный идентификатор песни
-                       "group" VARCHAR(255) NOT NULL,  -- Название группы
-                       song VARCHAR(255) NOT NULL,     -- Название песни
-                       release_date DATE,              -- Дата релиза
-                       text TEXT,                      -- Текст песни
-                       link VARCHAR(2083)             -- Ссылка на песню
-);
-
--- Добавляем индекс для быстрого поиска по группе и названию песни
-CREATE INDEX idx_group_song ON songs ("group", song);
+-- Скрипт инициализации контейнера PostgreSQL.
+-- База данных musicdb создается через переменную POSTGRES_DB, а схема
+-- управляется миграциями приложения (database/scripts, команда "migrate").
+-- Таблицы здесь не создаются, чтобы схема не расходилась с миграциями.
+SELECT 1;
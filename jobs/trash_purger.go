@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"music-library/repository"
+)
+
+// TrashPurger периодически окончательно удаляет песни, пролежавшие в корзине дольше срока хранения.
+type TrashPurger struct {
+	Repo      repository.SongStore // Хранилище песен
+	Retention time.Duration        // Срок хранения песен в корзине
+	Interval  time.Duration        // Интервал между запусками очистки
+}
+
+// Run запускает очистку сразу и затем с интервалом Interval. Блокируется до отмены контекста.
+func (p *TrashPurger) Run(ctx context.Context) {
+	log.Printf("INFO: Trash purger started. Retention: %s, Interval: %s", p.Retention, p.Interval)
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("INFO: Trash purger stopped.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge удаляет песни, перемещенные в корзину раньше now - Retention.
+func (p *TrashPurger) purge(ctx context.Context) {
+	cutoff := time.Now().Add(-p.Retention)
+	purged, err := p.Repo.PurgeTrashedBefore(ctx, cutoff)
+	if err != nil {
+		if ctx.Err() != nil {
+			return // Очистка прервана остановкой приложения и продолжится при следующем запуске
+		}
+		log.Printf("ERROR: Trash purge failed: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("INFO: Purged %d songs deleted before %s", purged, cutoff.Format(time.RFC3339))
+	}
+}
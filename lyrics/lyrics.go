@@ -0,0 +1,78 @@
+package lyrics
+
+import "strings"
+
+// Режимы разбиения текста песни
+const (
+	ModeLines  = "lines"  // Каждая строка — отдельная часть (режим по умолчанию)
+	ModeVerses = "verses" // Куплеты, разделенные пустыми строками
+)
+
+// normalizeNewlines приводит переводы строк Windows (CRLF) и старого macOS (CR) к LF.
+func normalizeNewlines(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
+}
+
+// SplitLines разбивает текст песни на строки так же, как это делалось до появления куплетов:
+// пустые строки и пробелы сохраняются, чтобы номера строк не менялись для существующих клиентов.
+// Отличие одно — переводы строк CRLF и CR не оставляют "\r" в конце строк.
+func SplitLines(text string) []string {
+	return strings.Split(normalizeNewlines(text), "\n")
+}
+
+// SplitVerses разбивает текст песни на куплеты.
+// Куплеты разделяются одной или несколькими пустыми (или состоящими из пробелов) строками.
+// Строки внутри куплета обрезаются и соединяются через "\n".
+func SplitVerses(text string) []string {
+	verses := make([]string, 0)
+	var current []string
+
+	flush := func() {
+		if len(current) > 0 {
+			verses = append(verses, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(normalizeNewlines(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return verses
+}
+
+// Split разбивает текст в зависимости от режима: ModeLines или ModeVerses.
+// Для неизвестного режима второе значение равно false.
+func Split(text, mode string) ([]string, bool) {
+	switch mode {
+	case ModeLines:
+		return SplitLines(text), true
+	case ModeVerses:
+		return SplitVerses(text), true
+	default:
+		return nil, false
+	}
+}
+
+// VerseContaining возвращает номер куплета (начиная с 1), содержащего фрагмент текста.
+// Сравнение выполняется без учета регистра; пробелы внутри фрагмента схлопываются.
+// Если фрагмент не найден ни в одном куплете, возвращается 0.
+func VerseContaining(verses []string, excerpt string) int {
+	excerpt = strings.ToLower(strings.Join(strings.Fields(excerpt), " "))
+	if excerpt == "" {
+		return 0
+	}
+	for i, verse := range verses {
+		if strings.Contains(strings.ToLower(strings.Join(strings.Fields(verse), " ")), excerpt) {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitVerses(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: []string{}},
+		{name: "only blank lines", text: "\n \n\t\n", want: []string{}},
+		{name: "single verse", text: "a\nb", want: []string{"a\nb"}},
+		{name: "blank line separator", text: "a\nb\n\nc\nd", want: []string{"a\nb", "c\nd"}},
+		{name: "several separators and whitespace", text: "\n\n  a  \n\n \n\n\tb\n\n", want: []string{"a", "b"}},
+		{name: "crlf", text: "a\r\nb\r\n\r\nc\r\n", want: []string{"a\nb", "c"}},
+		{name: "old macos cr", text: "a\rb\r\rc", want: []string{"a\nb", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitVerses(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitVerses(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	text := "  a\r\nb\r\n\r\nc\n"
+	tests := []struct {
+		mode   string
+		want   []string
+		wantOK bool
+	}{
+		// Режим строк повторяет strings.Split(text, "\n"), но без "\r"
+		{mode: ModeLines, want: []string{"  a", "b", "", "c", ""}, wantOK: true},
+		{mode: ModeVerses, want: []string{"a\nb", "c"}, wantOK: true},
+		{mode: "stanzas", want: nil, wantOK: false},
+		{mode: "", want: nil, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, ok := Split(text, tt.mode)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q, %q) = %q, %v, want %q, %v", text, tt.mode, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestVerseContaining(t *testing.T) {
+	verses := []string{"Is this the real life?\nIs this just fantasy?", "Mama, just killed a man"}
+	tests := []struct {
+		excerpt string
+		want    int
+	}{
+		{excerpt: "just  FANTASY", want: 1},
+		{excerpt: "life? is this", want: 1}, // Перевод строки внутри куплета сравнивается как пробел
+		{excerpt: "killed a man", want: 2},
+		{excerpt: "galileo", want: 0},
+		{excerpt: "  ", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := VerseContaining(verses, tt.excerpt); got != tt.want {
+			t.Errorf("VerseContaining(%q) = %d, want %d", tt.excerpt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// startedAtKey — ключ, под которым в экземпляре запроса GORM хранится время его начала
+const startedAtKey = "metrics:started_at"
+
+// songCountTimeout ограничивает время подсчета песен при каждом сборе метрик
+const songCountTimeout = 2 * time.Second
+
+var (
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by GORM operation and table.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14), // От 0,5 мс до ~4 с
+	}, []string{"operation", "table"})
+
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Failed database queries by GORM operation and table (record not found is not an error).",
+	}, []string{"operation", "table"})
+)
+
+// InstrumentDB подключает метрики базы данных: время и ошибки запросов GORM,
+// статистику пула соединений (go_sql_*) и количество песен в библиотеке.
+//
+// Принимает:
+//   - db *gorm.DB: соединение с базой данных.
+//
+// Возвращает:
+//   - error: ошибка регистрации обработчиков GORM или метрик.
+func InstrumentDB(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := errors.Join(
+		callbacks.Create().Before("gorm:create").Register("metrics:before_create", startQuery),
+		callbacks.Create().After("gorm:create").Register("metrics:after_create", finishQuery("create")),
+		callbacks.Query().Before("gorm:query").Register("metrics:before_query", startQuery),
+		callbacks.Query().After("gorm:query").Register("metrics:after_query", finishQuery("query")),
+		callbacks.Update().Before("gorm:update").Register("metrics:before_update", startQuery),
+		callbacks.Update().After("gorm:update").Register("metrics:after_update", finishQuery("update")),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", startQuery),
+		callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", finishQuery("delete")),
+		callbacks.Row().Before("gorm:row").Register("metrics:before_row", startQuery),
+		callbacks.Row().After("gorm:row").Register("metrics:after_row", finishQuery("row")),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", startQuery),
+		callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", finishQuery("raw")),
+	); err != nil {
+		return fmt.Errorf("failed to register metrics callbacks: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return errors.Join(
+		Registry.Register(collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name())),
+		Registry.Register(&songCollector{db: db}),
+	)
+}
+
+// startQuery запоминает время начала запроса
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(startedAtKey, time.Now())
+}
+
+// finishQuery возвращает обработчик, который учитывает время и ошибку завершенного запроса
+func finishQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startedAtKey)
+		if !ok {
+			return
+		}
+		started, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown" // Произвольные SQL-запросы (Raw, Exec)
+		}
+		dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(started).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			dbQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
+
+// songCollector сообщает количество песен в библиотеке и в корзине.
+// Песни подсчитываются запросом к базе при каждом сборе метрик.
+type songCollector struct {
+	db *gorm.DB
+}
+
+// songsDesc описывает метрику количества песен
+var songsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "songs"),
+	"Songs in the library by state: active or trashed.",
+	[]string{"state"}, nil,
+)
+
+// Describe передает описание метрики количества песен.
+func (c *songCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- songsDesc
+}
+
+// Collect подсчитывает песни. При ошибке запроса метрика не передается.
+func (c *songCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), songCountTimeout)
+	defer cancel()
+
+	var counts struct {
+		Active  int64
+		Trashed int64
+	}
+	if err := c.db.WithContext(ctx).Raw(`SELECT
+			COALESCE(SUM(CASE WHEN deleted_at IS NULL THEN 1 ELSE 0 END), 0) AS active,
+			COALESCE(SUM(CASE WHEN deleted_at IS NOT NULL THEN 1 ELSE 0 END), 0) AS trashed
+		FROM songs`).Scan(&counts).Error; err != nil {
+		log.Printf("ERROR: Failed to count songs for metrics: %v", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(songsDesc, prometheus.GaugeValue, float64(counts.Active), "active")
+	ch <- prometheus.MustNewConstMetric(songsDesc, prometheus.GaugeValue, float64(counts.Trashed), "trashed")
+}
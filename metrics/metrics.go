@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace — общий префикс имен метрик приложения
+const namespace = "music_library"
+
+// Результаты поиска информации о песне в GET /info (значения метки outcome).
+// Для песен, полученных из источников, результатом считается наименее приоритетный
+// источник, из которого взято хотя бы одно поле: локальный файл или внешний API.
+// Значения меток не зависят от имен источников в providers и не меняются вместе с ними.
+const (
+	LookupDatabase  = "database"     // Песня найдена в базе данных (точно или нечетким поиском)
+	LookupLocalFile = "local_file"   // Песня получена из локального файла
+	LookupUpstream  = "upstream_api" // Хотя бы одно поле получено из внешнего API
+	LookupNotFound  = "not_found"    // Песня не найдена ни в одном источнике
+	LookupFailed    = "failed"       // Ошибка базы данных или источника, разомкнут выключатель
+)
+
+// Registry — реестр метрик приложения. Кроме метрик приложения содержит метрики
+// среды выполнения Go и процесса.
+var Registry = prometheus.NewRegistry()
+
+// EnrichmentLookups считает запросы GET /info по результату поиска песни.
+var EnrichmentLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "enrichment_lookups_total",
+	Help:      "Song info lookups by outcome: database, local_file, upstream_api, not_found or failed.",
+}, []string{"outcome"})
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		EnrichmentLookups,
+		httpRequests,
+		httpRequestDuration,
+		httpRequestsInFlight,
+		dbQueryDuration,
+		dbQueryErrors,
+	)
+}
+
+// Handler возвращает HTTP-обработчик, отдающий метрики в формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Album представляет собой альбом исполнителя.
+type Album struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	ArtistID    *int       `json:"artist_id"`
+	Artist      *Artist    `json:"artist,omitempty"`
+	ReleaseDate *time.Time `json:"release_date"`
+	CoverLink   string     `json:"cover_link"`   // Ссылка на обложку
+	TotalTracks *int       `json:"total_tracks"` // Общее количество треков
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BeforeSave не дает сохранять исполнителя через альбом: связь задается только полем ArtistID.
+func (a *Album) BeforeSave(_ *gorm.DB) error {
+	a.Artist = nil
+	return nil
+}
+
+// AlbumRequest описывает тело запроса на создание или обновление альбома.
+type AlbumRequest struct {
+	Title       string `json:"title" binding:"required,max=255"`
+	ArtistID    *int   `json:"artist_id" binding:"omitempty,min=1"`
+	ReleaseDate string `json:"release_date" binding:"omitempty,datetime=2006-01-02"`
+	CoverLink   string `json:"cover_link" binding:"omitempty,url,max=2083"`
+	TotalTracks *int   `json:"total_tracks" binding:"omitempty,min=1"`
+}
+
+// AlbumListResponse представляет страницу списка альбомов.
+type AlbumListResponse struct {
+	Albums []Album `json:"albums"`
+	Total  int64   `json:"total"`
+	Page   int     `json:"page"`
+	Limit  int     `json:"limit"`
+}
+
+// AlbumTrackRequest описывает позицию песни в альбоме.
+type AlbumTrackRequest struct {
+	TrackNumber int `json:"track_number" binding:"required,min=1"`
+	DiscNumber  int `json:"disc_number" binding:"omitempty,min=1"` // По умолчанию 1
+}
+
+// AlbumTracksResponse представляет альбом со списком треков по порядку.
+type AlbumTracksResponse struct {
+	Album  Album  `json:"album"`
+	Tracks []Song `json:"tracks"`
+}
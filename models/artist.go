@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"music-library/normalize"
+)
+
+// Artist представляет собой исполнителя (группу).
+type Artist struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	NameKey    string    `json:"-"`           // Нормализованное имя (см. normalize.Fold), уникально для каждого исполнителя
+	SortName   string    `json:"sort_name"`   // Имя для сортировки, например "Beatles, The"
+	Country    string    `json:"country"`     // Страна происхождения
+	FormedYear *int      `json:"formed_year"` // Год образования
+	Aliases    []string  `json:"aliases" gorm:"serializer:json"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// BeforeSave заполняет нормализованное имя и имя для сортировки перед сохранением.
+func (a *Artist) BeforeSave(_ *gorm.DB) error {
+	a.Name = strings.TrimSpace(a.Name)
+	a.NameKey = normalize.Fold(a.Name)
+	if strings.TrimSpace(a.SortName) == "" {
+		a.SortName = a.Name
+	}
+	if a.Aliases == nil {
+		a.Aliases = []string{}
+	}
+	return nil
+}
+
+// ArtistRequest описывает тело запроса на создание или обновление исполнителя.
+type ArtistRequest struct {
+	Name       string   `json:"name" binding:"required,max=255"`
+	SortName   string   `json:"sort_name" binding:"max=255"`
+	Country    string   `json:"country" binding:"max=100"`
+	FormedYear *int     `json:"formed_year" binding:"omitempty,min=1000,max=9999"`
+	Aliases    []string `json:"aliases" binding:"omitempty,dive,max=255"`
+}
+
+// ArtistListResponse представляет страницу списка исполнителей.
+type ArtistListResponse struct {
+	Artists []Artist `json:"artists"`
+	Total   int64    `json:"total"`
+	Page    int      `json:"page"`
+	Limit   int      `json:"limit"`
+}
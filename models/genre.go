@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"music-library/normalize"
+)
+
+// Genre представляет собой жанр. Жанры образуют иерархию (например, Rock > Alternative).
+type Genre struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	NameKey   string    `json:"-"`         // Нормализованное имя для поиска и проверки уникальности
+	ParentID  *int      `json:"parent_id"` // Родительский жанр (nil для жанров верхнего уровня)
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BeforeSave приводит имя жанра к каноническому виду и заполняет ключ нормализации.
+func (g *Genre) BeforeSave(_ *gorm.DB) error {
+	g.Name = strings.TrimSpace(g.Name)
+	g.NameKey = normalize.Name(g.Name)
+	return nil
+}
+
+// Tag представляет собой пользовательский тег песни.
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"` // Нормализованное имя тега
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeSave нормализует имя тега, чтобы "Summer  Hits" и "summer hits" были одним тегом.
+func (t *Tag) BeforeSave(_ *gorm.DB) error {
+	t.Name = normalize.Name(t.Name)
+	return nil
+}
+
+// GenreRequest описывает тело запроса на создание или обновление жанра.
+type GenreRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	ParentID *int   `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+// GenreListResponse представляет список жанров.
+type GenreListResponse struct {
+	Genres []Genre `json:"genres"`
+	Total  int64   `json:"total"`
+}
+
+// TagListResponse представляет страницу списка тегов.
+type TagListResponse struct {
+	Tags  []Tag `json:"tags"`
+	Total int64 `json:"total"`
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+}
+
+// SongGenresRequest описывает полный набор жанров песни.
+type SongGenresRequest struct {
+	GenreIDs []int `json:"genre_ids" binding:"omitempty,dive,min=1"`
+}
+
+// SongTagsRequest описывает набор тегов песни.
+type SongTagsRequest struct {
+	Tags []string `json:"tags" binding:"omitempty,dive,max=64"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Состояния сервиса и его зависимостей
+const (
+	HealthOK       = "ok"       // Работает
+	HealthDegraded = "degraded" // Работает с ограничениями (например, недоступен внешний API)
+	HealthDown     = "down"     // Не работает
+)
+
+// DependencyStatus представляет результат проверки одной зависимости.
+type DependencyStatus struct {
+	Status    string                 `json:"status"`            // HealthOK, HealthDegraded или HealthDown
+	Critical  bool                   `json:"critical"`          // Без зависимости сервис не готов принимать запросы
+	LatencyMS float64                `json:"latency_ms"`        // Время проверки в миллисекундах
+	Error     string                 `json:"error,omitempty"`   // Причина неудачной проверки
+	Details   map[string]interface{} `json:"details,omitempty"` // Дополнительные сведения (версия схемы, состояние выключателя)
+}
+
+// ReadinessResponse представляет ответ GET /readyz.
+type ReadinessResponse struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
+
+// DBPoolStats представляет статистику пула соединений (sql.DBStats).
+type DBPoolStats struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`           // Сколько раз запросу пришлось ждать свободное соединение
+	WaitDurationMS     float64 `json:"wait_duration_ms"`     // Суммарное время ожидания соединений
+	MaxIdleClosed      int64   `json:"max_idle_closed"`      // Закрыто из-за ограничения неактивных соединений
+	MaxIdleTimeClosed  int64   `json:"max_idle_time_closed"` // Закрыто из-за времени простоя
+	MaxLifetimeClosed  int64   `json:"max_lifetime_closed"`  // Закрыто из-за времени жизни
+}
+
+// BuildInfo представляет сведения о сборке.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"` // Коммит, из которого собран бинарный файл
+	GoVersion string `json:"go_version"`
+}
+
+// StatusResponse представляет ответ GET /status.
+type StatusResponse struct {
+	Status        string                      `json:"status"`
+	Build         BuildInfo                   `json:"build"`
+	StartedAt     time.Time                   `json:"started_at"`
+	UptimeSeconds float64                     `json:"uptime_seconds"`
+	Dependencies  map[string]DependencyStatus `json:"dependencies"`
+	DBPool        DBPoolStats                 `json:"db_pool"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Видимость плейлиста.
+const (
+	VisibilityPrivate = "private" // Виден только владельцу
+	VisibilityPublic  = "public"  // Виден всем в общем списке
+)
+
+// Playlist представляет собой пользовательский плейлист.
+type Playlist struct {
+	ID          int             `json:"id"`
+	Owner       string          `json:"owner"` // Идентификатор владельца
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Visibility  string          `json:"visibility"` // private или public
+	Entries     []PlaylistEntry `json:"entries,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// BeforeSave не дает сохранять записи через плейлист: они изменяются только через API записей.
+func (p *Playlist) BeforeSave(_ *gorm.DB) error {
+	p.Entries = nil
+	return nil
+}
+
+// PlaylistEntry представляет собой песню на определенной позиции плейлиста.
+// ID записи не меняется при перемещении, позиции в плейлисте идут подряд начиная с 1.
+//
+// Если песня перемещена в корзину, запись сохраняет свою позицию, но Song не заполняется,
+// а Available равно false; после восстановления песни запись снова становится доступной.
+// При окончательном удалении песни запись удаляется, а следующие за ней сдвигаются.
+type PlaylistEntry struct {
+	ID         int       `json:"id"`
+	PlaylistID int       `json:"playlist_id"`
+	SongID     int       `json:"song_id"`
+	Position   int       `json:"position"`
+	Song       *Song     `json:"song,omitempty"`
+	Available  bool      `json:"available" gorm:"-"` // Песня не находится в корзине (заполняется при загрузке)
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// PlaylistRequest описывает тело запроса на создание или обновление плейлиста.
+type PlaylistRequest struct {
+	Owner       string `json:"owner" binding:"required,max=255"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"max=2000"`
+	Visibility  string `json:"visibility" binding:"omitempty,oneof=private public"` // По умолчанию private
+}
+
+// PlaylistEntryRequest описывает добавление песни в плейлист.
+// Если позиция не указана, песня добавляется в конец.
+type PlaylistEntryRequest struct {
+	SongID   int  `json:"song_id" binding:"required,min=1"`
+	Position *int `json:"position" binding:"omitempty,min=1"`
+}
+
+// MovePlaylistEntryRequest описывает перемещение записи на новую позицию.
+type MovePlaylistEntryRequest struct {
+	Position int `json:"position" binding:"required,min=1"`
+}
+
+// DuplicatePlaylistRequest описывает копирование плейлиста.
+// Пустые поля берутся из исходного плейлиста (к названию добавляется " (copy)").
+type DuplicatePlaylistRequest struct {
+	Owner      string `json:"owner" binding:"max=255"`
+	Name       string `json:"name" binding:"max=255"`
+	Visibility string `json:"visibility" binding:"omitempty,oneof=private public"`
+}
+
+// PlaylistListResponse представляет страницу списка плейлистов.
+type PlaylistListResponse struct {
+	Playlists []Playlist `json:"playlists"`
+	Total     int64      `json:"total"`
+	Page      int        `json:"page"`
+	Limit     int        `json:"limit"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// DefaultSearchLanguage — конфигурация поиска без стемминга, подходящая для любого языка.
+const DefaultSearchLanguage = "simple"
+
+// SearchLanguages — встроенные конфигурации полнотекстового поиска PostgreSQL,
+// которые можно указать в качестве языка песни или запроса.
+var SearchLanguages = map[string]struct{}{
+	"simple": {}, "arabic": {}, "danish": {}, "dutch": {}, "english": {}, "finnish": {},
+	"french": {}, "german": {}, "greek": {}, "hungarian": {}, "indonesian": {}, "irish": {},
+	"italian": {}, "lithuanian": {}, "nepali": {}, "norwegian": {}, "portuguese": {},
+	"romanian": {}, "russian": {}, "spanish": {}, "swedish": {}, "tamil": {}, "turkish": {},
+}
+
+// IsSearchLanguage сообщает, поддерживается ли конфигурация поиска.
+func IsSearchLanguage(language string) bool {
+	_, ok := SearchLanguages[language]
+	return ok
+}
+
+// SearchResult представляет одну найденную песню.
+type SearchResult struct {
+	Song       Song    `json:"song"`
+	Rank       float64 `json:"rank"`                  // Релевантность (ts_rank)
+	Snippet    string  `json:"snippet"`               // Фрагмент текста в HTML: текст экранирован, совпадения выделены <b>...</b>
+	VerseIndex int     `json:"verse_index,omitempty"` // Номер куплета с совпадением (начиная с 1), 0 — совпадение не в тексте
+	Verse      string  `json:"verse,omitempty"`       // Текст куплета с совпадением
+}
+
+// SearchResponse представляет страницу результатов поиска.
+type SearchResponse struct {
+	Query    string         `json:"query"`
+	Mode     string         `json:"mode"`
+	Language string         `json:"language"`
+	Results  []SearchResult `json:"results"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	Limit    int            `json:"limit"`
+}
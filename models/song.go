@@ -1,23 +1,134 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"music-library/normalize"
+)
 
 // Song представляет собой модель песни.
 type Song struct {
-	ID          int       `json:"id"`
-	Group       string    `json:"group"`
-	Song        string    `json:"song"`
-	Text        string    `json:"text"`
-	ReleaseDate time.Time `json:"release_date"`
-	Link        string    `json:"link"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	DeletedAt   time.Time `json:"deleted_at"`
+	ID          int            `json:"id"`
+	Group       string         `json:"group"`
+	GroupKey    string         `json:"-"` // Нормализованное название группы (см. normalize.Fold)
+	ArtistID    *int           `json:"artist_id"`
+	Artist      *Artist        `json:"artist,omitempty"`
+	Song        string         `json:"song"`
+	SongKey     string         `json:"-"` // Нормализованное название песни (см. normalize.Fold)
+	Text        string         `json:"text"`
+	ReleaseDate time.Time      `json:"release_date"`
+	Link        string         `json:"link"`
+	Language    string         `json:"language" gorm:"column:search_language"` // Конфигурация полнотекстового поиска PostgreSQL (см. SearchLanguages)
+	AlbumID     *int           `json:"album_id"`
+	TrackNumber *int           `json:"track_number"` // Номер трека на диске альбома
+	DiscNumber  *int           `json:"disc_number"`  // Номер диска альбома
+	Genres      []Genre        `json:"genres,omitempty" gorm:"many2many:song_genres"`
+	Tags        []Tag          `json:"tags,omitempty" gorm:"many2many:song_tags"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index" swaggertype:"string"` // Время перемещения в корзину (мягкое удаление)
+}
+
+// BeforeSave заполняет нормализованные ключи и связывает песню с исполнителем по названию группы.
+// Исполнитель ищется по нормализованному имени и создается, если его еще нет.
+// Связанные объекты Artist, Genres и Tags через песню не сохраняются: они изменяются
+// только через API исполнителей, жанров и тегов.
+func (s *Song) BeforeSave(tx *gorm.DB) error {
+	s.Artist, s.Genres, s.Tags = nil, nil, nil
+	if s.Language == "" {
+		s.Language = DefaultSearchLanguage
+	}
+	s.GroupKey, s.SongKey = normalize.Fold(s.Group), normalize.Fold(s.Song)
+	// Ключ исполнителя совпадает с ключом группы песни: "Beyoncé" и "beyonce" — один исполнитель
+	key := s.GroupKey
+	if key == "" {
+		s.ArtistID = nil
+		return nil
+	}
+
+	db := tx.Session(&gorm.Session{NewDB: true})
+	artist := Artist{Name: s.Group}
+	if err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name_key"}}, DoNothing: true}).
+		Create(&artist).Error; err != nil {
+		return err
+	}
+	if artist.ID == 0 {
+		// Исполнитель уже существует (в том числе создан параллельным запросом)
+		if err := db.Where("name_key = ?", key).First(&artist).Error; err != nil {
+			return err
+		}
+	}
+	s.ArtistID = &artist.ID
+	return nil
 }
 
 // SongDetail представляет более подробную информацию о песне.
 type SongDetail struct {
-	Link        string `json:"link"`
-	ReleaseDate string `json:"release_date"`
+	Link        string            `json:"link"`
+	ReleaseDate string            `json:"release_date"`
+	Text        string            `json:"text"`
+	Sources     map[string]string `json:"sources,omitempty"` // Источник каждого поля (поле -> имя источника)
+	Match       *SongMatch        `json:"match,omitempty"`   // Найденная нечетким поиском песня, если запрос не совпал с ней точно
+}
+
+// SongMatch описывает существующую песню, похожую на запрошенную.
+type SongMatch struct {
+	ID         int     `json:"id"`
+	Group      string  `json:"group"`
+	Song       string  `json:"song"`
+	Similarity float64 `json:"similarity"` // Сходство с запросом от 0 до 1 (pg_trgm)
+}
+
+// SongSuggestionsResponse возвращается, если запрошенная песня не найдена точно,
+// но в библиотеке есть похожие песни.
+type SongSuggestionsResponse struct {
+	Error       string      `json:"error"`
+	Message     string      `json:"message"`
+	Suggestions []SongMatch `json:"suggestions"`
+}
+
+// CreateSongRequest описывает тело запроса на создание песни.
+type CreateSongRequest struct {
+	Group       string `json:"group" binding:"required,max=255"`
+	Song        string `json:"song" binding:"required,max=255"`
+	ReleaseDate string `json:"release_date" binding:"required,datetime=2006-01-02"`
 	Text        string `json:"text"`
+	Link        string `json:"link" binding:"omitempty,url,max=2083"`
+	Language    string `json:"language" binding:"omitempty,max=64"` // Язык текста для поиска (по умолчанию simple)
+}
+
+// SongListResponse представляет страницу списка песен.
+// При курсорной пагинации поле Page не заполняется.
+type SongListResponse struct {
+	Songs      []Song `json:"songs"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"` // Токен для запроса следующей страницы
+}
+
+// Verse представляет часть текста песни (куплет или строку).
+type Verse struct {
+	Index int    `json:"index"` // Порядковый номер части в тексте (начиная с 1)
+	Text  string `json:"text"`
+}
+
+// SongVersesResponse представляет страницу текста песни.
+type SongVersesResponse struct {
+	SongID int     `json:"song_id"`
+	Mode   string  `json:"mode"` // Режим разбиения: lines или verses
+	Verses []Verse `json:"verses"`
+	Total  int     `json:"total"` // Общее количество частей в тексте
+	Page   int     `json:"page"`
+	Limit  int     `json:"limit"`
+	Pages  int     `json:"pages"` // Общее количество страниц
+}
+
+// ErrorResponse описывает структурированную ошибку API.
+type ErrorResponse struct {
+	Error   string `json:"error"`           // Машиночитаемый код ошибки
+	Field   string `json:"field,omitempty"` // Параметр запроса, вызвавший ошибку
+	Message string `json:"message"`         // Описание ошибки
 }
@@ -0,0 +1,74 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Name приводит название группы или песни к виду для сравнения:
+// нижний регистр, без пробелов по краям, последовательности пробелов заменены одним пробелом.
+//
+// Должна соответствовать SQL-выражению lower(regexp_replace(btrim(x), '\s+', ' ', 'g')),
+// которое используется в миграциях для заполнения нормализованных колонок.
+func Name(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+// letterFolds заменяет буквы, которые не раскладываются в Unicode на базовую букву
+// и диакритический знак, по аналогии со словарем unaccent PostgreSQL.
+var letterFolds = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "ð", "d", "þ", "th", "ı", "i",
+)
+
+// Fold дополнительно к Name убирает диакритические знаки: "Beyoncé" и "beyonce" дают один ключ.
+// Буква "ё" приводится к "е", как это делает unaccent.
+//
+// Соответствует SQL-выражению lower(regexp_replace(btrim(unaccent(x)), '\s+', ' ', 'g'))
+// для букв, которые встречаются в названиях на практике.
+func Fold(value string) string {
+	decomposed := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(decomposed, Name(value))
+	if err != nil {
+		folded = Name(value)
+	}
+	return letterFolds.Replace(folded)
+}
+
+// Similarity возвращает сходство двух строк от 0 до 1 по тому же алгоритму, что и функция
+// similarity расширения pg_trgm: доля общих триграмм слов среди всех триграмм обеих строк.
+// Используется там, где сходство нельзя вычислить в базе данных.
+func Similarity(a, b string) float64 {
+	left, right := trigrams(a), trigrams(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+
+	common := 0
+	for trigram := range left {
+		if _, ok := right[trigram]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(left)+len(right)-common)
+}
+
+// trigrams возвращает множество триграмм строки. Как и в pg_trgm, строка разбивается на слова
+// из букв и цифр, а каждое слово дополняется двумя пробелами в начале и одним в конце.
+func trigrams(value string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	set := make(map[string]struct{})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
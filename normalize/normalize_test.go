@@ -0,0 +1,88 @@
+package normalize
+
+import "testing"
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"Muse", "muse"},
+		{"  The  Rolling\tStones \n", "the rolling stones"},
+		{"AC/DC", "ac/dc"},
+		{"Beyoncé", "beyoncé"},
+		{"ДДТ", "ддт"},
+	}
+
+	for _, tt := range tests {
+		if got := Name(tt.value); got != tt.want {
+			t.Errorf("Name(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"  The  Rolling\tStones ", "the rolling stones"},
+		{"Beyoncé", "beyonce"},
+		{"Beyoncé", "beyonce"}, // Уже разложенная форма
+		{"Motörhead", "motorhead"},
+		{"Sigur Rós", "sigur ros"},
+		{"Straße", "strasse"},
+		{"Æther", "aether"},
+		{"Œuvre", "oeuvre"},
+		{"Mø", "mo"},
+		{"Łódź", "lodz"},
+		{"Đorđe", "dorde"},
+		{"Þór", "thor"},
+		{"Ёлка", "елка"},
+		{"Й", "и"},
+	}
+
+	for _, tt := range tests {
+		if got := Fold(tt.value); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 0},
+		{"muse", "", 0},
+		{"!!!", "???", 0},
+		{"Muse", "muse", 1},
+		{"Rolling Stones", "stones, rolling", 1},
+		{"abc", "abd", 2.0 / 6},
+		{"word", "two words", 4.0 / 11}, // Пример из документации pg_trgm: 0.36363637
+		{"queen", "abba", 0},
+	}
+
+	for _, tt := range tests {
+		got := Similarity(tt.a, tt.b)
+		if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if reverse := Similarity(tt.b, tt.a); reverse != got {
+			t.Errorf("Similarity(%q, %q) = %v, not symmetric with %v", tt.b, tt.a, reverse, got)
+		}
+	}
+}
+
+func TestSimilarityOfFoldedNames(t *testing.T) {
+	if got := Similarity(Fold("Motörhead"), Fold("Motorhead")); got != 1 {
+		t.Errorf("Similarity of folded names = %v, want 1", got)
+	}
+	if got := Similarity("Motörhead", "Motorhead"); got >= 1 {
+		t.Errorf("Similarity without folding = %v, want < 1", got)
+	}
+}
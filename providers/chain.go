@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"music-library/models"
+)
+
+// Поля SongDetail, которые заполняются источниками
+const (
+	FieldReleaseDate = "release_date"
+	FieldText        = "text"
+	FieldLink        = "link"
+)
+
+// Имена источников в SongDetail.Sources
+const (
+	SourceDatabase    = "database"     // Собственная база данных
+	SourceLocalFile   = "local_file"   // Локальный файл (FileProvider)
+	SourceUpstreamAPI = "upstream_api" // Внешний API (HTTPProvider)
+)
+
+// Chain объединяет несколько источников в порядке убывания приоритета.
+// Каждое поле берется из самого приоритетного источника, в котором оно не пустое;
+// менее приоритетные источники опрашиваются только пока остаются незаполненные поля.
+type Chain struct {
+	providers []EnrichmentProvider
+}
+
+// NewChain создает цепочку источников. Первый источник имеет наивысший приоритет.
+func NewChain(providers ...EnrichmentProvider) *Chain {
+	return &Chain{providers: providers}
+}
+
+// Name возвращает имя источника.
+func (c *Chain) Name() string {
+	return "chain"
+}
+
+// FetchSongDetail собирает информацию о песне из всех источников цепочки.
+func (c *Chain) FetchSongDetail(ctx context.Context, group, song string) (models.SongDetail, error) {
+	return c.Enrich(ctx, group, song, models.SongDetail{})
+}
+
+// Enrich дополняет незаполненные поля base данными из источников цепочки.
+// Уже заполненные поля base имеют наивысший приоритет; их источник берется из base.Sources.
+//
+// Возвращает:
+//   - models.SongDetail: объединенные данные с указанием источника каждого поля в Sources.
+//   - error: ErrSongNotFound, если ни один источник не знает песню, или ошибку источника,
+//     если ни одно поле так и не удалось заполнить.
+func (c *Chain) Enrich(ctx context.Context, group, song string, base models.SongDetail) (models.SongDetail, error) {
+	result := base
+	result.Sources = make(map[string]string)
+	for field, source := range base.Sources {
+		result.Sources[field] = source
+	}
+
+	var firstErr error
+	for _, provider := range c.providers {
+		if isComplete(result) {
+			break
+		}
+
+		detail, err := provider.FetchSongDetail(ctx, group, song)
+		if errors.Is(err, ErrSongNotFound) {
+			continue
+		}
+		if err != nil {
+			log.Printf("WARNING: Enrichment source '%s' failed: %v", provider.Name(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		mergeField(&result.ReleaseDate, detail.ReleaseDate, FieldReleaseDate, provider.Name(), result.Sources)
+		mergeField(&result.Text, detail.Text, FieldText, provider.Name(), result.Sources)
+		mergeField(&result.Link, detail.Link, FieldLink, provider.Name(), result.Sources)
+	}
+
+	if len(result.Sources) == 0 {
+		result.Sources = nil
+		if firstErr != nil {
+			return result, firstErr
+		}
+		return result, ErrSongNotFound
+	}
+	return result, nil
+}
+
+// mergeField заполняет пустое поле значением из источника и запоминает источник.
+func mergeField(target *string, value, field, source string, sources map[string]string) {
+	if *target != "" || value == "" {
+		return
+	}
+	*target = value
+	sources[field] = source
+}
+
+// isComplete сообщает, заполнены ли все поля SongDetail.
+func isComplete(detail models.SongDetail) bool {
+	return detail.ReleaseDate != "" && detail.Text != "" && detail.Link != ""
+}
+
+// DetailSources возвращает карту источников для непустых полей detail, полученных из source.
+func DetailSources(detail models.SongDetail, source string) map[string]string {
+	sources := make(map[string]string)
+	if detail.ReleaseDate != "" {
+		sources[FieldReleaseDate] = source
+	}
+	if detail.Text != "" {
+		sources[FieldText] = source
+	}
+	if detail.Link != "" {
+		sources[FieldLink] = source
+	}
+	return sources
+}
@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"music-library/models"
+)
+
+// stubProvider — источник с заранее заданным ответом, считающий обращения к себе.
+type stubProvider struct {
+	name   string
+	detail models.SongDetail
+	err    error
+	calls  int
+}
+
+func (p *stubProvider) Name() string {
+	return p.name
+}
+
+func (p *stubProvider) FetchSongDetail(_ context.Context, _, _ string) (models.SongDetail, error) {
+	p.calls++
+	return p.detail, p.err
+}
+
+func TestChainEnrich(t *testing.T) {
+	errUpstream := errors.New("upstream is down")
+	full := models.SongDetail{ReleaseDate: "2006-07-16", Text: "text", Link: "https://example.com"}
+
+	tests := []struct {
+		name      string
+		base      models.SongDetail
+		providers []*stubProvider
+		want      models.SongDetail
+		wantErr   error
+		wantCalls []int
+	}{
+		{
+			name:      "first provider is complete",
+			providers: []*stubProvider{{name: "a", detail: full}, {name: "b", detail: full}},
+			want:      models.SongDetail{ReleaseDate: full.ReleaseDate, Text: full.Text, Link: full.Link, Sources: map[string]string{FieldReleaseDate: "a", FieldText: "a", FieldLink: "a"}},
+			wantCalls: []int{1, 0},
+		},
+		{
+			name: "fields merged by priority",
+			providers: []*stubProvider{
+				{name: "a", detail: models.SongDetail{Text: "from a"}},
+				{name: "b", detail: models.SongDetail{Text: "from b", Link: "link b"}},
+				{name: "c", detail: models.SongDetail{ReleaseDate: "2000-01-01", Link: "link c"}},
+			},
+			want:      models.SongDetail{ReleaseDate: "2000-01-01", Text: "from a", Link: "link b", Sources: map[string]string{FieldReleaseDate: "c", FieldText: "a", FieldLink: "b"}},
+			wantCalls: []int{1, 1, 1},
+		},
+		{
+			name:      "base fields win",
+			base:      models.SongDetail{Text: "stored", Sources: map[string]string{FieldText: SourceDatabase}},
+			providers: []*stubProvider{{name: "a", detail: full}},
+			want:      models.SongDetail{ReleaseDate: full.ReleaseDate, Text: "stored", Link: full.Link, Sources: map[string]string{FieldReleaseDate: "a", FieldText: SourceDatabase, FieldLink: "a"}},
+			wantCalls: []int{1},
+		},
+		{
+			name:      "failed provider is skipped",
+			providers: []*stubProvider{{name: "a", err: errUpstream}, {name: "b", detail: models.SongDetail{Text: "text"}}},
+			want:      models.SongDetail{Text: "text", Sources: map[string]string{FieldText: "b"}},
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "not found anywhere",
+			providers: []*stubProvider{{name: "a", err: ErrSongNotFound}, {name: "b", err: ErrSongNotFound}},
+			wantErr:   ErrSongNotFound,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "error wins over not found",
+			providers: []*stubProvider{{name: "a", err: ErrSongNotFound}, {name: "b", err: errUpstream}},
+			wantErr:   errUpstream,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "empty answer is not found",
+			providers: []*stubProvider{{name: "a"}},
+			wantErr:   ErrSongNotFound,
+			wantCalls: []int{1},
+		},
+		{name: "empty chain", wantErr: ErrSongNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]EnrichmentProvider, 0, len(tt.providers))
+			for _, provider := range tt.providers {
+				providers = append(providers, provider)
+			}
+
+			got, err := NewChain(providers...).Enrich(context.Background(), "Muse", "Uprising", tt.base)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Enrich() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Enrich() = %+v, want %+v", got, tt.want)
+			}
+			for i, provider := range tt.providers {
+				if provider.calls != tt.wantCalls[i] {
+					t.Errorf("provider %s calls = %d, want %d", provider.name, provider.calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChainEnrichKeepsBaseSources(t *testing.T) {
+	base := models.SongDetail{Text: "stored", Sources: map[string]string{FieldText: SourceDatabase}}
+	if _, err := NewChain(&stubProvider{name: "a", detail: models.SongDetail{Link: "link"}}).Enrich(context.Background(), "g", "s", base); err != nil {
+		t.Fatal(err)
+	}
+	if len(base.Sources) != 1 {
+		t.Errorf("Enrich() modified base.Sources: %v", base.Sources)
+	}
+}
+
+func TestDetailSources(t *testing.T) {
+	got := DetailSources(models.SongDetail{Text: "text", Link: "link"}, SourceLocalFile)
+	want := map[string]string{FieldText: SourceLocalFile, FieldLink: SourceLocalFile}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetailSources() = %v, want %v", got, want)
+	}
+}
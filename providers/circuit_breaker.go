@@ -0,0 +1,126 @@
+package providers
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen возвращается, пока автоматический выключатель разомкнут и запросы к источнику не выполняются.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState — состояние автоматического выключателя.
+type CircuitState string
+
+// Состояния автоматического выключателя
+const (
+	CircuitClosed   CircuitState = "closed"    // Запросы выполняются как обычно
+	CircuitOpen     CircuitState = "open"      // Запросы отклоняются без обращения к источнику
+	CircuitHalfOpen CircuitState = "half-open" // Разрешен один пробный запрос
+)
+
+// CircuitBreaker размыкается после FailureThreshold неудачных вызовов подряд
+// и через OpenTimeout пропускает один пробный вызов. Успешный пробный вызов замыкает цепь,
+// неудачный — снова размыкает ее.
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // Количество неудач подряд
+	openedAt time.Time // Момент размыкания
+	probing  bool      // Выполняется ли пробный вызов в полуоткрытом состоянии
+}
+
+// NewCircuitBreaker создает замкнутый автоматический выключатель.
+// Значение failureThreshold <= 0 отключает размыкание.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            CircuitClosed,
+	}
+}
+
+// Allow проверяет, можно ли выполнить вызов. Возвращает ErrCircuitOpen, если вызов запрещен.
+// Каждый разрешенный вызов должен завершаться вызовом Success или Failure.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success фиксирует успешный вызов и замыкает цепь.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure фиксирует неудачный вызов и при достижении порога размыкает цепь.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == CircuitHalfOpen || (b.failureThreshold > 0 && b.failures >= b.failureThreshold) {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State возвращает текущее состояние выключателя.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.openTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// RetryAfter возвращает время до следующего пробного вызова (0, если цепь не разомкнута).
+func (b *CircuitBreaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitOpen {
+		return 0
+	}
+	if remaining := b.openTimeout - time.Since(b.openedAt); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Abandon освобождает разрешение на вызов без учета результата,
+// например когда запрос отменен клиентом и о состоянии источника ничего не известно.
+func (b *CircuitBreaker) Abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
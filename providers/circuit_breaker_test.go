@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	breaker := NewCircuitBreaker(2, 20*time.Millisecond)
+
+	steps := []struct {
+		name    string
+		action  func()
+		state   CircuitState
+		allowed bool
+	}{
+		{name: "initially closed", action: func() {}, state: CircuitClosed, allowed: true},
+		{name: "first failure keeps closed", action: breaker.Failure, state: CircuitClosed, allowed: true},
+		{name: "success resets failures", action: breaker.Success, state: CircuitClosed, allowed: true},
+		{name: "one failure after reset", action: breaker.Failure, state: CircuitClosed, allowed: true},
+		{name: "threshold opens", action: breaker.Failure, state: CircuitOpen, allowed: false},
+		{name: "half-open after timeout", action: func() { time.Sleep(30 * time.Millisecond) }, state: CircuitHalfOpen, allowed: true},
+		{name: "failed probe opens again", action: breaker.Failure, state: CircuitOpen, allowed: false},
+		{name: "second probe", action: func() { time.Sleep(30 * time.Millisecond) }, state: CircuitHalfOpen, allowed: true},
+		{name: "successful probe closes", action: breaker.Success, state: CircuitClosed, allowed: true},
+	}
+
+	for _, step := range steps {
+		step.action()
+		if state := breaker.State(); state != step.state {
+			t.Fatalf("%s: State() = %s, want %s", step.name, state, step.state)
+		}
+		err := breaker.Allow()
+		if allowed := err == nil; allowed != step.allowed {
+			t.Fatalf("%s: Allow() error = %v, want allowed %v", step.name, err, step.allowed)
+		}
+		if err != nil && !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("%s: Allow() error = %v, want ErrCircuitOpen", step.name, err)
+		}
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	breaker := NewCircuitBreaker(1, 0)
+	breaker.Failure()
+
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("first probe Allow() error = %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("concurrent probe Allow() error = %v, want ErrCircuitOpen", err)
+	}
+
+	// Прерванный пробный вызов освобождает место для следующего
+	breaker.Abandon()
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("probe after Abandon() error = %v", err)
+	}
+}
+
+func TestCircuitBreakerRetryAfter(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Minute)
+	if got := breaker.RetryAfter(); got != 0 {
+		t.Errorf("closed RetryAfter() = %s, want 0", got)
+	}
+	breaker.Failure()
+	if got := breaker.RetryAfter(); got <= 0 || got > time.Minute {
+		t.Errorf("open RetryAfter() = %s, want within (0, 1m]", got)
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	breaker := NewCircuitBreaker(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		breaker.Failure()
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("Allow() with threshold 0 error = %v", err)
+	}
+}
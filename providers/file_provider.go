@@ -0,0 +1,201 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"music-library/models"
+	"music-library/normalize"
+)
+
+// SongEnrichment структура для данных, обогащающих информацию о песне
+type SongEnrichment struct {
+	Group       string `json:"group"`        // Группа исполнителей
+	Song        string `json:"song"`         // Название песни
+	ReleaseDate string `json:"release_date"` // Дата релиза песни
+	Text        string `json:"text"`         // Текст песни
+	Link        string `json:"link"`         // Ссылка на внешний источник
+}
+
+// FileProvider получает информацию о песне из локального файла.
+//
+// Файл может содержать JSON-массив объектов SongEnrichment, один объект
+// или поток объектов (JSON Lines). Содержимое загружается в память в виде индекса
+// по нормализованной (без учета регистра, пробелов и диакритики) паре группа+песня
+// и перечитывается при изменении файла (см. Watch).
+// Если новая версия файла не разбирается, продолжает использоваться предыдущая.
+type FileProvider struct {
+	path string
+
+	mu      sync.RWMutex
+	index   map[string]SongEnrichment
+	modTime time.Time // Время изменения загруженной версии файла
+	size    int64     // Размер загруженной версии файла
+}
+
+// NewFileProvider создает источник и загружает данные из файла по указанному пути.
+// Ошибка загрузки только логируется: источник остается пустым до появления корректного файла.
+func NewFileProvider(path string) *FileProvider {
+	provider := &FileProvider{path: path, index: make(map[string]SongEnrichment)}
+	if err := provider.Reload(); err != nil {
+		log.Printf("WARNING: Enrichment file %s is not loaded: %v", path, err)
+	}
+	return provider
+}
+
+// Name возвращает имя источника.
+func (p *FileProvider) Name() string {
+	return SourceLocalFile
+}
+
+// FetchSongDetail ищет песню в загруженном индексе.
+func (p *FileProvider) FetchSongDetail(_ context.Context, group, song string) (models.SongDetail, error) {
+	p.mu.RLock()
+	enrichmentData, ok := p.index[enrichmentKey(group, song)]
+	p.mu.RUnlock()
+
+	if !ok {
+		return models.SongDetail{}, ErrSongNotFound
+	}
+	return models.SongDetail{
+		ReleaseDate: enrichmentData.ReleaseDate,
+		Text:        enrichmentData.Text,
+		Link:        enrichmentData.Link,
+	}, nil
+}
+
+// Len возвращает количество песен в загруженном индексе.
+func (p *FileProvider) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.index)
+}
+
+// Reload перечитывает файл и заменяет индекс.
+// При ошибке чтения или разбора текущий индекс не изменяется.
+func (p *FileProvider) Reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+
+	byteValue, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать файл: %w", err)
+	}
+
+	entries, err := parseEnrichments(byteValue)
+	if err != nil {
+		// Запоминаем версию файла, чтобы не пытаться разбирать ее повторно
+		p.mu.Lock()
+		p.modTime, p.size = info.ModTime(), info.Size()
+		p.mu.Unlock()
+		return fmt.Errorf("не удалось разобрать файл: %w", err)
+	}
+
+	index := make(map[string]SongEnrichment, len(entries))
+	for _, entry := range entries {
+		key := enrichmentKey(entry.Group, entry.Song)
+		if _, exists := index[key]; exists {
+			log.Printf("WARNING: Duplicate enrichment for '%s' by '%s' in %s, using the last one", entry.Song, entry.Group, p.path)
+		}
+		index[key] = entry
+	}
+
+	p.mu.Lock()
+	p.index = index
+	p.modTime, p.size = info.ModTime(), info.Size()
+	p.mu.Unlock()
+
+	log.Printf("INFO: Loaded %d song enrichments from %s", len(index), p.path)
+	return nil
+}
+
+// Watch периодически проверяет время изменения и размер файла и перечитывает его при изменении.
+// Блокируется до отмены контекста.
+func (p *FileProvider) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !p.changed() {
+				continue
+			}
+			if err := p.Reload(); err != nil {
+				log.Printf("ERROR: Failed to reload enrichment file %s, keeping previous data: %v", p.path, err)
+			}
+		}
+	}
+}
+
+// changed сообщает, изменился ли файл с момента последней загрузки.
+func (p *FileProvider) changed() bool {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return !info.ModTime().Equal(p.modTime) || info.Size() != p.size
+}
+
+// parseEnrichments разбирает содержимое файла: JSON-массив, один объект или JSON Lines.
+func parseEnrichments(data []byte) ([]SongEnrichment, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if trimmed[0] == '[' {
+		var entries []SongEnrichment
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, err
+		}
+		return validateEnrichments(entries)
+	}
+
+	// Один объект или последовательность объектов (JSON Lines)
+	var entries []SongEnrichment
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var entry SongEnrichment
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return validateEnrichments(entries)
+}
+
+// validateEnrichments проверяет, что у каждой записи указаны группа и песня.
+func validateEnrichments(entries []SongEnrichment) ([]SongEnrichment, error) {
+	for i, entry := range entries {
+		if strings.TrimSpace(entry.Group) == "" || strings.TrimSpace(entry.Song) == "" {
+			return nil, fmt.Errorf("entry %d: group and song are required", i+1)
+		}
+	}
+	return entries, nil
+}
+
+// enrichmentKey строит ключ индекса из нормализованных названий группы и песни.
+func enrichmentKey(group, song string) string {
+	return normalize.Fold(group) + "\x00" + normalize.Fold(song)
+}
@@ -0,0 +1,209 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"music-library/models"
+)
+
+func TestParseEnrichments(t *testing.T) {
+	muse := SongEnrichment{Group: "Muse", Song: "Uprising", ReleaseDate: "2009-09-07"}
+	queen := SongEnrichment{Group: "Queen", Song: "Bohemian Rhapsody", Link: "https://example.com"}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []SongEnrichment
+		wantErr bool
+	}{
+		{name: "empty", data: " \n\t"},
+		{name: "empty array", data: "[]", want: []SongEnrichment{}},
+		{
+			name: "array",
+			data: `[{"group":"Muse","song":"Uprising","release_date":"2009-09-07"},{"group":"Queen","song":"Bohemian Rhapsody","link":"https://example.com"}]`,
+			want: []SongEnrichment{muse, queen},
+		},
+		{
+			name: "single object",
+			data: `  {"group":"Muse","song":"Uprising","release_date":"2009-09-07"}  `,
+			want: []SongEnrichment{muse},
+		},
+		{
+			name: "json lines",
+			data: "{\"group\":\"Muse\",\"song\":\"Uprising\",\"release_date\":\"2009-09-07\"}\n\n{\"group\":\"Queen\",\"song\":\"Bohemian Rhapsody\",\"link\":\"https://example.com\"}\n",
+			want: []SongEnrichment{muse, queen},
+		},
+		{name: "broken array", data: `[{"group":"Muse"`, wantErr: true},
+		{name: "broken json lines", data: "{\"group\":\"Muse\",\"song\":\"Uprising\"}\n{\"group\":", wantErr: true},
+		{name: "not an object", data: `"Muse"`, wantErr: true},
+		{name: "missing song in array", data: `[{"group":"Muse"}]`, wantErr: true},
+		{name: "missing group in json lines", data: "{\"group\":\"Muse\",\"song\":\"Uprising\"}\n{\"song\":\"Uprising\"}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEnrichments([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEnrichments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEnrichments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEnrichments(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []SongEnrichment
+		wantErr string
+	}{
+		{name: "nil"},
+		{name: "valid", entries: []SongEnrichment{{Group: "Muse", Song: "Uprising"}, {Group: "Queen", Song: "Innuendo"}}},
+		{name: "empty group", entries: []SongEnrichment{{Song: "Uprising"}}, wantErr: "entry 1: group and song are required"},
+		{name: "blank song", entries: []SongEnrichment{{Group: "Muse", Song: "Uprising"}, {Group: "Muse", Song: "  "}}, wantErr: "entry 2: group and song are required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateEnrichments(tt.entries)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("validateEnrichments() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateEnrichments() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.entries) {
+				t.Errorf("validateEnrichments() = %+v, want %+v", got, tt.entries)
+			}
+		})
+	}
+}
+
+// writeEnrichments записывает файл и выставляет ему заданное время изменения.
+func writeEnrichments(t *testing.T, path, data string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileProviderFetchSongDetail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "songs.json")
+	writeEnrichments(t, path, `[
+		{"group":"Motörhead","song":"Ace of Spades","release_date":"1980-10-27","text":"old"},
+		{"group":"Motorhead","song":"Ace  of Spades","text":"new"}
+	]`, time.Now())
+
+	provider := NewFileProvider(path)
+	if provider.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1 (duplicates by folded key)", provider.Len())
+	}
+
+	got, err := provider.FetchSongDetail(context.Background(), " MOTORHEAD ", "ace of spades")
+	if err != nil {
+		t.Fatalf("FetchSongDetail() error = %v", err)
+	}
+	if want := (models.SongDetail{Text: "new"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchSongDetail() = %+v, want %+v (last duplicate wins)", got, want)
+	}
+
+	if _, err := provider.FetchSongDetail(context.Background(), "Motorhead", "Overkill"); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("FetchSongDetail() error = %v, want ErrSongNotFound", err)
+	}
+}
+
+func TestFileProviderMissingFile(t *testing.T) {
+	provider := NewFileProvider(filepath.Join(t.TempDir(), "missing.json"))
+	if provider.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", provider.Len())
+	}
+	if provider.changed() {
+		t.Error("changed() = true for a missing file")
+	}
+	if _, err := provider.FetchSongDetail(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("FetchSongDetail() error = %v, want ErrSongNotFound", err)
+	}
+}
+
+func TestFileProviderReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "songs.json")
+	start := time.Now().Add(-time.Hour)
+	writeEnrichments(t, path, `{"group":"Muse","song":"Uprising","text":"v1"}`, start)
+
+	provider := NewFileProvider(path)
+	if provider.changed() {
+		t.Fatal("changed() = true right after loading")
+	}
+
+	// Новая версия файла подхватывается
+	writeEnrichments(t, path, "{\"group\":\"Muse\",\"song\":\"Uprising\",\"text\":\"v2\"}\n{\"group\":\"Muse\",\"song\":\"Resistance\"}", start.Add(time.Minute))
+	if !provider.changed() {
+		t.Fatal("changed() = false after the file was rewritten")
+	}
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if provider.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", provider.Len())
+	}
+	detail, _ := provider.FetchSongDetail(context.Background(), "Muse", "Uprising")
+	if detail.Text != "v2" {
+		t.Errorf("Text = %q, want v2", detail.Text)
+	}
+
+	// Некорректная версия не заменяет индекс и не разбирается повторно
+	writeEnrichments(t, path, `[{"group":"Muse"}]`, start.Add(2*time.Minute))
+	if err := provider.Reload(); err == nil {
+		t.Fatal("Reload() error = nil for an invalid file")
+	}
+	if provider.Len() != 2 {
+		t.Errorf("Len() = %d after a failed reload, want 2", provider.Len())
+	}
+	if provider.changed() {
+		t.Error("changed() = true for the already rejected version")
+	}
+}
+
+func TestFileProviderWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "songs.json")
+	start := time.Now().Add(-time.Hour)
+	writeEnrichments(t, path, `[]`, start)
+
+	provider := NewFileProvider(path)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		provider.Watch(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	writeEnrichments(t, path, `[{"group":"Muse","song":"Uprising"}]`, start.Add(time.Minute))
+	deadline := time.Now().Add(2 * time.Second)
+	for provider.Len() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Watch() did not pick up the changed file")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch() did not return after the context was canceled")
+	}
+}
@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"music-library/config"
+	"music-library/models"
+)
+
+// HTTPProvider получает информацию о песне из внешнего API (GET {BaseURL}/info?group=...&song=...).
+// Неудачные запросы (сетевые ошибки, 5xx, 429) повторяются с экспоненциальной задержкой,
+// а после серии неудачных вызовов автоматический выключатель временно прекращает обращения к API.
+type HTTPProvider struct {
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+	retry   RetryPolicy
+	breaker *CircuitBreaker
+}
+
+// NewHTTPProvider создает клиента внешнего API.
+//
+// Принимает:
+//   - cfg config.EnrichmentAPIConfig: адрес, таймаут, заголовки, параметры повторов и выключателя.
+//
+// Возвращает:
+//   - *HTTPProvider: клиент внешнего API.
+func NewHTTPProvider(cfg config.EnrichmentAPIConfig) *HTTPProvider {
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		headers: cfg.Headers,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		retry: RetryPolicy{
+			MaxAttempts: maxAttempts,
+			BaseDelay:   cfg.BackoffBase,
+			MaxDelay:    cfg.BackoffMax,
+		},
+		breaker: NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerTimeout),
+	}
+}
+
+// Name возвращает имя источника.
+func (p *HTTPProvider) Name() string {
+	return SourceUpstreamAPI
+}
+
+// Breaker возвращает автоматический выключатель клиента.
+func (p *HTTPProvider) Breaker() *CircuitBreaker {
+	return p.breaker
+}
+
+// Ping проверяет доступность внешнего API одним запросом GET {BaseURL} без повторов.
+// Результат не учитывается выключателем. API считается доступным, если ответил
+// любым статусом, кроме 5xx.
+func (p *HTTPProvider) Ping(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	for name, value := range p.headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		return &StatusError{StatusCode: response.StatusCode}
+	}
+	return nil
+}
+
+// FetchSongDetail запрашивает информацию о песне во внешнем API.
+// Если выключатель разомкнут, сразу возвращает ErrCircuitOpen.
+func (p *HTTPProvider) FetchSongDetail(ctx context.Context, group, song string) (models.SongDetail, error) {
+	if err := p.breaker.Allow(); err != nil {
+		log.Printf("WARNING: External API call skipped, circuit breaker is %s", p.breaker.State())
+		return models.SongDetail{}, err
+	}
+
+	detail, err := p.fetchWithRetry(ctx, group, song)
+	switch {
+	case err == nil, errors.Is(err, ErrSongNotFound):
+		p.breaker.Success()
+	case ctx.Err() != nil:
+		// Запрос отменен вызывающей стороной — это не говорит о состоянии API
+		p.breaker.Abandon()
+	case isDependencyFailure(err):
+		p.breaker.Failure()
+	default:
+		p.breaker.Success()
+	}
+	return detail, err
+}
+
+// fetchWithRetry выполняет запрос, повторяя его при временных ошибках.
+func (p *HTTPProvider) fetchWithRetry(ctx context.Context, group, song string) (models.SongDetail, error) {
+	var lastErr error
+	for attempt := 1; attempt <= p.retry.MaxAttempts; attempt++ {
+		detail, retryAfter, err := p.fetch(ctx, group, song)
+		if err == nil || !isDependencyFailure(err) || ctx.Err() != nil {
+			return detail, err
+		}
+		lastErr = err
+		if attempt == p.retry.MaxAttempts {
+			break
+		}
+
+		// Для 429 уважаем Retry-After, в остальных случаях — экспоненциальная задержка с джиттером
+		delay := p.retry.backoff(attempt)
+		if retryAfter > 0 {
+			// Не ждем дольше допустимой задержки и дольше, чем позволяет дедлайн запроса
+			delay = retryAfter
+			deadline, hasDeadline := ctx.Deadline()
+			if (p.retry.MaxDelay > 0 && delay > p.retry.MaxDelay) || (hasDeadline && time.Until(deadline) < delay) {
+				log.Printf("WARNING: External API asked to retry after %s, giving up", delay)
+				break
+			}
+		}
+
+		log.Printf("WARNING: External API attempt %d/%d failed: %v; retrying in %s", attempt, p.retry.MaxAttempts, err, delay)
+		if err := sleep(ctx, delay); err != nil {
+			return models.SongDetail{}, err
+		}
+	}
+	return models.SongDetail{}, lastErr
+}
+
+// fetch выполняет одну попытку запроса.
+// Второе значение — задержка из заголовка Retry-After ответа 429 (0, если заголовка нет).
+func (p *HTTPProvider) fetch(ctx context.Context, group, song string) (models.SongDetail, time.Duration, error) {
+	query := url.Values{}
+	query.Set("group", group)
+	query.Set("song", song)
+	apiURL := p.baseURL + "/info?" + query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return models.SongDetail{}, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	for name, value := range p.headers {
+		request.Header.Set(name, value)
+	}
+
+	// Запрос к внешнему API
+	response, err := p.client.Do(request)
+	if err != nil {
+		return models.SongDetail{}, 0, &NetworkError{Err: err}
+	}
+	defer response.Body.Close()
+
+	// Проверка статуса ответа
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return models.SongDetail{}, 0, ErrSongNotFound
+	case response.StatusCode == http.StatusTooManyRequests:
+		retryAfter, _ := parseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+		return models.SongDetail{}, retryAfter, &StatusError{StatusCode: response.StatusCode}
+	case response.StatusCode != http.StatusOK:
+		return models.SongDetail{}, 0, &StatusError{StatusCode: response.StatusCode}
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return models.SongDetail{}, 0, &NetworkError{Err: err}
+	}
+
+	var apiData models.SongDetail
+	if err := json.Unmarshal(body, &apiData); err != nil {
+		return models.SongDetail{}, 0, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	return apiData, 0, nil
+}
+
+// isDependencyFailure сообщает, вызвана ли ошибка недоступностью внешнего API
+// (сетевая ошибка, 5xx или 429), а не содержимым запроса.
+func isDependencyFailure(err error) bool {
+	var networkErr *NetworkError
+	if errors.As(err, &networkErr) {
+		return true
+	}
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && isRetryableStatus(statusErr.StatusCode)
+}
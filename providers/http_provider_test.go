@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"music-library/config"
+)
+
+var (
+	_ EnrichmentProvider = (*HTTPProvider)(nil)
+	_ EnrichmentProvider = (*FileProvider)(nil)
+	_ EnrichmentProvider = (*Chain)(nil)
+)
+
+// newTestAPI запускает тестовый внешний API, который отвечает обработчиком handler,
+// и возвращает клиента к нему со счетчиком запросов.
+func newTestAPI(t *testing.T, cfg config.EnrichmentAPIConfig, handler http.HandlerFunc) (*HTTPProvider, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg.BaseURL = server.URL + "/"
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second
+	}
+	return NewHTTPProvider(cfg), &calls
+}
+
+func TestHTTPProviderFetchSongDetail(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string // Ожидаемый текст песни
+		wantErr func(error) bool
+	}{
+		{name: "ok", status: http.StatusOK, body: `{"release_date":"2006-07-16","text":"Ooh baby","link":"https://example.com"}`, want: "Ooh baby"},
+		{name: "not found", status: http.StatusNotFound, wantErr: func(err error) bool { return errors.Is(err, ErrSongNotFound) }},
+		{
+			name:   "bad request is not retried",
+			status: http.StatusBadRequest,
+			wantErr: func(err error) bool {
+				var statusErr *StatusError
+				return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest
+			},
+		},
+		{name: "invalid json", status: http.StatusOK, body: `{`, wantErr: func(err error) bool { return err != nil && !isDependencyFailure(err) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, calls := newTestAPI(t, config.EnrichmentAPIConfig{
+				MaxAttempts: 3,
+				Headers:     map[string]string{"X-Api-Key": "secret"},
+			}, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/info" || r.URL.Query().Get("group") != "Muse & Co" || r.URL.Query().Get("song") != "Supermassive Black Hole" {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				if r.Header.Get("X-Api-Key") != "secret" {
+					t.Errorf("X-Api-Key = %q, want secret", r.Header.Get("X-Api-Key"))
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			detail, err := provider.FetchSongDetail(context.Background(), "Muse & Co", "Supermassive Black Hole")
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("FetchSongDetail() error = %v", err)
+				}
+			} else if err != nil || detail.Text != tt.want {
+				t.Fatalf("FetchSongDetail() = %+v, %v, want text %q", detail, err, tt.want)
+			}
+			if calls.Load() != 1 {
+				t.Errorf("API calls = %d, want 1", calls.Load())
+			}
+			if provider.Breaker().State() != CircuitClosed {
+				t.Errorf("breaker state = %s, want closed", provider.Breaker().State())
+			}
+		})
+	}
+}
+
+func TestHTTPProviderNetworkError(t *testing.T) {
+	provider := NewHTTPProvider(config.EnrichmentAPIConfig{BaseURL: "http://127.0.0.1:1", Timeout: time.Second, MaxAttempts: 1})
+
+	_, err := provider.FetchSongDetail(context.Background(), "Muse", "Uprising")
+	var networkErr *NetworkError
+	if !errors.As(err, &networkErr) {
+		t.Fatalf("FetchSongDetail() error = %v, want NetworkError", err)
+	}
+}
+
+func TestHTTPProviderPing(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusNotFound},
+		{status: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		provider, _ := newTestAPI(t, config.EnrichmentAPIConfig{}, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+		})
+		if err := provider.Ping(context.Background()); (err != nil) != tt.wantErr {
+			t.Errorf("Ping() with status %d error = %v, want error %v", tt.status, err, tt.wantErr)
+		}
+	}
+}
+
+func TestHTTPProviderOpensCircuit(t *testing.T) {
+	provider, calls := newTestAPI(t, config.EnrichmentAPIConfig{
+		MaxAttempts:      1,
+		BreakerThreshold: 2,
+		BreakerTimeout:   time.Minute,
+	}, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.FetchSongDetail(context.Background(), "Muse", "Uprising"); err == nil {
+			t.Fatal("FetchSongDetail() error = nil")
+		}
+	}
+	if _, err := provider.FetchSongDetail(context.Background(), "Muse", "Uprising"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("FetchSongDetail() error = %v, want ErrCircuitOpen", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("API calls = %d, want 2", calls.Load())
+	}
+}
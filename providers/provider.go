@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"music-library/models"
+)
+
+// ErrSongNotFound возвращается, если источник не знает запрошенную песню.
+var ErrSongNotFound = errors.New("song not found")
+
+// StatusError описывает неожиданный HTTP-статус ответа внешнего источника.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+}
+
+// NetworkError описывает ошибку соединения с внешним источником.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("network error: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// EnrichmentProvider — источник дополнительной информации о песне
+// (дата релиза, текст, ссылка).
+type EnrichmentProvider interface {
+	// Name возвращает имя источника, которое указывается в ответе для полученных из него полей.
+	Name() string
+
+	// FetchSongDetail возвращает информацию о песне по названию группы и песни.
+	// Если песня неизвестна источнику, возвращается ErrSongNotFound.
+	FetchSongDetail(ctx context.Context, group, song string) (models.SongDetail, error)
+}
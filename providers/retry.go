@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy задает параметры повторных попыток запроса.
+type RetryPolicy struct {
+	MaxAttempts int           // Максимальное количество попыток (включая первую)
+	BaseDelay   time.Duration // Задержка перед второй попыткой
+	MaxDelay    time.Duration // Верхняя граница задержки между попытками
+}
+
+// backoff возвращает задержку перед попыткой с номером attempt (начиная с 1 для первого повтора).
+// Используется экспоненциальный рост с полным джиттером: случайное значение в [0, base*2^(attempt-1)].
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// isRetryableStatus сообщает, имеет ли смысл повторять запрос с таким статусом ответа.
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// parseRetryAfter разбирает заголовок Retry-After (число секунд или HTTP-дата).
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep ожидает указанное время или отмену контекста.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
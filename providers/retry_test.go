@@ -0,0 +1,143 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"music-library/config"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 1, max: 100 * time.Millisecond},
+		{attempt: 2, max: 200 * time.Millisecond},
+		{attempt: 4, max: 800 * time.Millisecond},
+		{attempt: 5, max: time.Second}, // Ограничено MaxDelay
+		{attempt: 50, max: time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if delay := policy.backoff(tt.attempt); delay < 0 || delay > tt.max {
+				t.Fatalf("backoff(%d) = %s, want within [0, %s]", tt.attempt, delay, tt.max)
+			}
+		}
+	}
+
+	if delay := (RetryPolicy{}).backoff(3); delay != 0 {
+		t.Errorf("backoff without delays = %s, want 0", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", want: 0, wantOK: false},
+		{value: "5", want: 5 * time.Second, wantOK: true},
+		{value: "0", want: 0, wantOK: true},
+		{value: "-1", want: 0, wantOK: false},
+		{value: "soon", want: 0, wantOK: false},
+		{value: "Wed, 01 May 2024 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{value: "Wed, 01 May 2024 11:59:00 GMT", want: 0, wantOK: true}, // Дата в прошлом — повторять сразу
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	for code, want := range map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusBadRequest:          false,
+		http.StatusNotFound:            false,
+		http.StatusOK:                  false,
+	} {
+		if got := isRetryableStatus(code); got != want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestHTTPProviderRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int  // Статусы ответов по порядку; последний повторяется
+		retryAfter string // Заголовок Retry-After для ответов 429
+		wantCalls  int32
+		wantErr    bool
+	}{
+		{name: "recovers after 5xx", statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}, wantCalls: 3},
+		{name: "gives up after max attempts", statuses: []int{http.StatusInternalServerError}, wantCalls: 3, wantErr: true},
+		{name: "honors short retry-after", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, retryAfter: "0", wantCalls: 2},
+		{name: "retry-after above max delay", statuses: []int{http.StatusTooManyRequests}, retryAfter: "120", wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var call int
+			provider, calls := newTestAPI(t, config.EnrichmentAPIConfig{
+				MaxAttempts: 3,
+				BackoffBase: time.Millisecond,
+				BackoffMax:  10 * time.Millisecond,
+			}, func(w http.ResponseWriter, _ *http.Request) {
+				status := tt.statuses[min(call, len(tt.statuses)-1)]
+				call++
+				if status == http.StatusTooManyRequests && tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"text":"ok"}`))
+			})
+
+			_, err := provider.FetchSongDetail(context.Background(), "Muse", "Uprising")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchSongDetail() error = %v, want error %v", err, tt.wantErr)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("API calls = %d, want %d", calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestHTTPProviderRetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	provider, calls := newTestAPI(t, config.EnrichmentAPIConfig{
+		MaxAttempts:      5,
+		BackoffBase:      time.Hour,
+		BackoffMax:       time.Hour,
+		BreakerThreshold: 1,
+	}, func(w http.ResponseWriter, _ *http.Request) {
+		cancel() // Клиент уходит, пока провайдер ждет повтора
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := provider.FetchSongDetail(ctx, "Muse", "Uprising")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("FetchSongDetail() error = %v, want context.Canceled", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("API calls = %d, want 1", calls.Load())
+	}
+	// Отмена клиентом не считается отказом API
+	if provider.Breaker().State() != CircuitClosed {
+		t.Errorf("breaker state = %s, want closed", provider.Breaker().State())
+	}
+}
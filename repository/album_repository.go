@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"log"
+	"music-library/models"
+)
+
+// ErrAlbumArtistNotFound возвращается, если у альбома указан несуществующий исполнитель.
+var ErrAlbumArtistNotFound = errors.New("album artist not found")
+
+// ErrTrackPositionTaken возвращается, если позиция (диск и номер трека) в альбоме уже занята другой песней.
+var ErrTrackPositionTaken = errors.New("track position is already taken")
+
+// ErrTrackOutOfRange возвращается, если номер трека больше общего количества треков альбома.
+var ErrTrackOutOfRange = errors.New("track number exceeds album total tracks")
+
+// AlbumRepository предоставляет методы для работы с альбомами в базе данных.
+type AlbumRepository struct {
+	DB *gorm.DB // Экземпляр базы данных GORM
+}
+
+// GetAlbums получает список альбомов, упорядоченный по дате релиза.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - artistID *int: исполнитель для фильтрации (nil — без фильтра).
+//   - page int: номер страницы (начиная с 1).
+//   - limit int: количество записей на странице.
+//
+// Возвращает:
+//   - []models.Album: список альбомов вместе с исполнителями.
+//   - int64: общее количество альбомов, удовлетворяющих фильтру.
+//   - error: ошибка, если запрос не удался.
+func (repo *AlbumRepository) GetAlbums(ctx context.Context, artistID *int, page int, limit int) ([]models.Album, int64, error) {
+	query := func() *gorm.DB {
+		q := repo.DB.WithContext(ctx).Model(&models.Album{})
+		if artistID != nil {
+			q = q.Where("artist_id = ?", *artistID)
+		}
+		return q
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count albums. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	var albums []models.Album
+	offset := (page - 1) * limit
+	if err := query().Preload("Artist").Order("release_date, id").Limit(limit).Offset(offset).Find(&albums).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve albums. Error: %v\n", err)
+		return nil, 0, err
+	}
+	return albums, total, nil
+}
+
+// GetAlbumByID получает альбом вместе с исполнителем по идентификатору.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID альбома.
+//
+// Возвращает:
+//   - *models.Album: найденный альбом.
+//   - error: gorm.ErrRecordNotFound, если альбом не найден.
+func (repo *AlbumRepository) GetAlbumByID(ctx context.Context, id uint) (*models.Album, error) {
+	var album models.Album
+	if err := repo.DB.WithContext(ctx).Preload("Artist").First(&album, id).Error; err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// CreateAlbum сохраняет новый альбом.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - album *models.Album: альбом для сохранения.
+//
+// Возвращает:
+//   - *models.Album: сохраненный альбом вместе с исполнителем.
+//   - error: ErrAlbumArtistNotFound, если исполнитель не найден, или ошибка сохранения.
+func (repo *AlbumRepository) CreateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureAlbumArtistExists(tx, album.ArtistID); err != nil {
+			return err
+		}
+		return tx.Create(album).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to create album '%s'. Error: %v\n", album.Title, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully created album with ID: %d\n", album.ID)
+	return repo.GetAlbumByID(ctx, uint(album.ID))
+}
+
+// UpdateAlbum обновляет альбом.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - album *models.Album: обновленный альбом (с заполненным ID).
+//
+// Возвращает:
+//   - *models.Album: обновленный альбом вместе с исполнителем.
+//   - error: ErrAlbumArtistNotFound, если исполнитель не найден, или ошибка сохранения.
+func (repo *AlbumRepository) UpdateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureAlbumArtistExists(tx, album.ArtistID); err != nil {
+			return err
+		}
+		return tx.Save(album).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to update album with ID: %d. Error: %v\n", album.ID, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully updated album with ID: %d\n", album.ID)
+	return repo.GetAlbumByID(ctx, uint(album.ID))
+}
+
+// DeleteAlbum удаляет альбом. Песни альбома (включая песни в корзине) остаются
+// в библиотеке, но отвязываются от него вместе с номерами дисков и треков.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID альбома.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если альбом не найден, или ошибка удаления.
+func (repo *AlbumRepository) DeleteAlbum(ctx context.Context, id uint) error {
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Song{}).
+			Where("album_id = ?", id).
+			UpdateColumns(map[string]interface{}{"album_id": nil, "track_number": nil, "disc_number": nil}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Album{}, id)
+		if result.Error != nil {
+			log.Printf("ERROR: Failed to delete album with ID: %d. Error: %v\n", id, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		log.Printf("INFO: Successfully deleted album with ID: %d\n", id)
+		return nil
+	})
+}
+
+// GetAlbumTracks получает альбом и его песни в порядке дисков и номеров треков.
+// Песни в корзине в трек-лист не попадают.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID альбома.
+//
+// Возвращает:
+//   - *models.Album: альбом.
+//   - []models.Song: песни альбома по порядку.
+//   - error: gorm.ErrRecordNotFound, если альбом не найден, или ошибка запроса.
+func (repo *AlbumRepository) GetAlbumTracks(ctx context.Context, id uint) (*models.Album, []models.Song, error) {
+	album, err := repo.GetAlbumByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var songs []models.Song
+	if err := repo.DB.WithContext(ctx).Where("album_id = ?", id).Order("disc_number, track_number, id").Find(&songs).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve tracks of album with ID: %d. Error: %v\n", id, err)
+		return nil, nil, err
+	}
+	return album, songs, nil
+}
+
+// AttachSong помещает песню в альбом на указанную позицию. Если песня уже была
+// в другом альбоме или на другой позиции, она переносится.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - albumID uint: ID альбома.
+//   - songID uint: ID песни.
+//   - disc int: номер диска.
+//   - track int: номер трека на диске.
+//
+// Возвращает:
+//   - *models.Song: обновленная песня.
+//   - error: gorm.ErrRecordNotFound, если альбом или песня не найдены; ErrTrackOutOfRange,
+//     если номер трека больше количества треков альбома; ErrTrackPositionTaken, если позиция занята.
+func (repo *AlbumRepository) AttachSong(ctx context.Context, albumID, songID uint, disc, track int) (*models.Song, error) {
+	var song models.Song
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var album models.Album
+		if err := tx.First(&album, albumID).Error; err != nil {
+			return err
+		}
+		if album.TotalTracks != nil && track > *album.TotalTracks {
+			return ErrTrackOutOfRange
+		}
+		if err := tx.First(&song, songID).Error; err != nil {
+			return err
+		}
+
+		var occupied int64
+		if err := tx.Model(&models.Song{}).
+			Where("album_id = ? AND disc_number = ? AND track_number = ? AND id <> ?", albumID, disc, track, songID).
+			Count(&occupied).Error; err != nil {
+			return err
+		}
+		if occupied > 0 {
+			return ErrTrackPositionTaken
+		}
+
+		albumIDValue := int(albumID)
+		song.AlbumID, song.DiscNumber, song.TrackNumber = &albumIDValue, &disc, &track
+		return tx.Model(&song).UpdateColumns(map[string]interface{}{
+			"album_id":     albumIDValue,
+			"disc_number":  disc,
+			"track_number": track,
+		}).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to attach song with ID: %d to album with ID: %d. Error: %v\n", songID, albumID, err)
+		return nil, err
+	}
+	log.Printf("INFO: Attached song with ID: %d to album with ID: %d as disc %d track %d\n", songID, albumID, disc, track)
+	return &song, nil
+}
+
+// DetachSong убирает песню из альбома.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - albumID uint: ID альбома.
+//   - songID uint: ID песни.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если песни нет в этом альбоме.
+func (repo *AlbumRepository) DetachSong(ctx context.Context, albumID, songID uint) error {
+	result := repo.DB.WithContext(ctx).Model(&models.Song{}).
+		Where("id = ? AND album_id = ?", songID, albumID).
+		UpdateColumns(map[string]interface{}{"album_id": nil, "track_number": nil, "disc_number": nil})
+	if result.Error != nil {
+		log.Printf("ERROR: Failed to detach song with ID: %d from album with ID: %d. Error: %v\n", songID, albumID, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	log.Printf("INFO: Detached song with ID: %d from album with ID: %d\n", songID, albumID)
+	return nil
+}
+
+// ensureAlbumArtistExists проверяет, что указанный исполнитель альбома существует.
+func ensureAlbumArtistExists(tx *gorm.DB, artistID *int) error {
+	if artistID == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&models.Artist{}).Where("id = ?", *artistID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrAlbumArtistNotFound
+	}
+	return nil
+}
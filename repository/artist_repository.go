@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"log"
+	"music-library/models"
+	"music-library/normalize"
+)
+
+// ErrDuplicateArtist возвращается, если исполнитель с таким же (нормализованным) именем уже существует.
+var ErrDuplicateArtist = errors.New("artist with the same name already exists")
+
+// ErrArtistHasSongs возвращается при попытке удалить исполнителя, у которого есть песни.
+var ErrArtistHasSongs = errors.New("artist has songs")
+
+// ErrArtistHasAlbums возвращается при попытке удалить исполнителя, у которого есть альбомы.
+var ErrArtistHasAlbums = errors.New("artist has albums")
+
+// ArtistRepository предоставляет методы для работы с исполнителями в базе данных.
+type ArtistRepository struct {
+	DB *gorm.DB // Экземпляр базы данных GORM
+}
+
+// GetArtists получает список исполнителей, упорядоченный по имени для сортировки.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - name string: подстрока имени для фильтрации (пустая строка — без фильтра).
+//   - page int: номер страницы (начиная с 1).
+//   - limit int: количество записей на странице.
+//
+// Возвращает:
+//   - []models.Artist: список исполнителей.
+//   - int64: общее количество исполнителей, удовлетворяющих фильтру.
+//   - error: ошибка, если запрос не удался.
+func (repo *ArtistRepository) GetArtists(ctx context.Context, name string, page int, limit int) ([]models.Artist, int64, error) {
+	query := func() *gorm.DB {
+		q := repo.DB.WithContext(ctx).Model(&models.Artist{})
+		if name != "" {
+			q = q.Where("name_key LIKE ? ESCAPE '\\'", containsPattern(normalize.Fold(name)))
+		}
+		return q
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count artists. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	var artists []models.Artist
+	offset := (page - 1) * limit
+	if err := query().Order("LOWER(sort_name), id").Limit(limit).Offset(offset).Find(&artists).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve artists. Error: %v\n", err)
+		return nil, 0, err
+	}
+	return artists, total, nil
+}
+
+// GetArtistByID получает исполнителя по его идентификатору.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID исполнителя.
+//
+// Возвращает:
+//   - *models.Artist: найденный исполнитель.
+//   - error: gorm.ErrRecordNotFound, если исполнитель не найден.
+func (repo *ArtistRepository) GetArtistByID(ctx context.Context, id uint) (*models.Artist, error) {
+	var artist models.Artist
+	if err := repo.DB.WithContext(ctx).First(&artist, id).Error; err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+// CreateArtist сохраняет нового исполнителя.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - artist *models.Artist: исполнитель для сохранения.
+//
+// Возвращает:
+//   - *models.Artist: сохраненный исполнитель.
+//   - error: ErrDuplicateArtist, если имя уже занято, или ошибка сохранения.
+func (repo *ArtistRepository) CreateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureArtistNameFree(tx, artist.Name, 0); err != nil {
+			return err
+		}
+		return tx.Create(artist).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// Имя занял параллельный запрос между проверкой и вставкой
+		err = ErrDuplicateArtist
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to create artist '%s'. Error: %v\n", artist.Name, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully created artist with ID: %d\n", artist.ID)
+	return artist, nil
+}
+
+// UpdateArtist обновляет исполнителя. При изменении имени название группы
+// у всех песен исполнителя обновляется в той же транзакции.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - artist *models.Artist: обновленный исполнитель (с заполненным ID).
+//
+// Возвращает:
+//   - *models.Artist: обновленный исполнитель.
+//   - error: ErrDuplicateArtist, если новое имя занято другим исполнителем, или ошибка сохранения.
+func (repo *ArtistRepository) UpdateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureArtistNameFree(tx, artist.Name, artist.ID); err != nil {
+			return err
+		}
+		if err := tx.Save(artist).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Song{}).
+			Where("artist_id = ?", artist.ID).
+			UpdateColumns(map[string]interface{}{"group": artist.Name, "group_key": normalize.Fold(artist.Name)}).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// Имя занял параллельный запрос между проверкой и сохранением
+		err = ErrDuplicateArtist
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to update artist with ID: %d. Error: %v\n", artist.ID, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully updated artist with ID: %d\n", artist.ID)
+	return artist, nil
+}
+
+// DeleteArtist удаляет исполнителя, у которого нет песен (включая песни в корзине) и альбомов.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID исполнителя.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если исполнитель не найден; ErrArtistHasSongs или ErrArtistHasAlbums, если у него есть песни или альбомы.
+func (repo *ArtistRepository) DeleteArtist(ctx context.Context, id uint) error {
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var songs int64
+		if err := tx.Unscoped().Model(&models.Song{}).Where("artist_id = ?", id).Count(&songs).Error; err != nil {
+			return err
+		}
+		if songs > 0 {
+			return ErrArtistHasSongs
+		}
+
+		var albums int64
+		if err := tx.Model(&models.Album{}).Where("artist_id = ?", id).Count(&albums).Error; err != nil {
+			return err
+		}
+		if albums > 0 {
+			return ErrArtistHasAlbums
+		}
+
+		result := tx.Delete(&models.Artist{}, id)
+		if result.Error != nil {
+			log.Printf("ERROR: Failed to delete artist with ID: %d. Error: %v\n", id, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		log.Printf("INFO: Successfully deleted artist with ID: %d\n", id)
+		return nil
+	})
+}
+
+// GetArtistSongs получает песни исполнителя, упорядоченные по дате релиза.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID исполнителя.
+//   - page int: номер страницы (начиная с 1).
+//   - limit int: количество записей на странице.
+//
+// Возвращает:
+//   - []models.Song: песни исполнителя.
+//   - int64: общее количество песен исполнителя.
+//   - error: gorm.ErrRecordNotFound, если исполнитель не найден, или ошибка запроса.
+func (repo *ArtistRepository) GetArtistSongs(ctx context.Context, id uint, page int, limit int) ([]models.Song, int64, error) {
+	db := repo.DB.WithContext(ctx)
+	if _, err := repo.GetArtistByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := db.Model(&models.Song{}).Where("artist_id = ?", id).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var songs []models.Song
+	offset := (page - 1) * limit
+	if err := db.Where("artist_id = ?", id).Order("release_date, id").Limit(limit).Offset(offset).Find(&songs).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve songs of artist with ID: %d. Error: %v\n", id, err)
+		return nil, 0, err
+	}
+	return songs, total, nil
+}
+
+// ensureArtistNameFree проверяет, что нормализованное имя не занято другим исполнителем.
+func ensureArtistNameFree(tx *gorm.DB, name string, exceptID int) error {
+	var count int64
+	if err := tx.Model(&models.Artist{}).
+		Where("name_key = ? AND id <> ?", normalize.Fold(name), exceptID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateArtist
+	}
+	return nil
+}
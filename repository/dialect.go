@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+	"music-library/models"
+	"music-library/normalize"
+)
+
+// isPostgres сообщает, работает ли соединение с PostgreSQL. Полнотекстовый поиск и индекс
+// триграмм есть только в PostgreSQL; на других базах (SQLite) используются упрощенные варианты.
+func isPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
+
+// rankSimilarSongs вычисляет сходство ключа с названиями песен так же, как pg_trgm,
+// и возвращает не более limit песен, начиная с самой похожей.
+func rankSimilarSongs(songs []models.Song, key string, limit int) []models.SongMatch {
+	matches := make([]models.SongMatch, 0, len(songs))
+	for _, song := range songs {
+		matches = append(matches, models.SongMatch{
+			ID:         song.ID,
+			Group:      song.Group,
+			Song:       song.Song,
+			Similarity: normalize.Similarity(song.GroupKey+" "+song.SongKey, key),
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Similarity != matches[j].Similarity {
+			return matches[i].Similarity > matches[j].Similarity
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
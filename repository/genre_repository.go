@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"log"
+	"music-library/models"
+	"music-library/normalize"
+)
+
+// genreSubtreeSQL — рекурсивный запрос, возвращающий ID жанра и всех его потомков.
+// Единственный параметр — ID корневого жанра.
+const genreSubtreeSQL = `WITH RECURSIVE subtree AS (
+	SELECT id FROM genres WHERE id = ?
+	UNION ALL
+	SELECT g.id FROM genres g JOIN subtree ON g.parent_id = subtree.id
+) SELECT id FROM subtree`
+
+// ErrDuplicateGenre возвращается, если жанр с таким же (нормализованным) названием уже существует.
+var ErrDuplicateGenre = errors.New("genre with the same name already exists")
+
+// ErrGenreParentNotFound возвращается, если указан несуществующий родительский жанр.
+var ErrGenreParentNotFound = errors.New("parent genre not found")
+
+// ErrGenreCycle возвращается, если новый родитель жанра является им самим или его потомком.
+var ErrGenreCycle = errors.New("genre cannot be nested into itself or its descendant")
+
+// ErrGenreHasChildren возвращается при попытке удалить жанр, у которого есть поджанры.
+var ErrGenreHasChildren = errors.New("genre has subgenres")
+
+// ErrGenreNotFound возвращается, если в наборе жанров песни указан несуществующий жанр.
+var ErrGenreNotFound = errors.New("genre not found")
+
+// songGenre — строка связующей таблицы песен и жанров.
+type songGenre struct {
+	SongID  int `gorm:"primaryKey"`
+	GenreID int `gorm:"primaryKey"`
+}
+
+// TableName задает имя таблицы для songGenre.
+func (songGenre) TableName() string {
+	return "song_genres"
+}
+
+// GenreRepository предоставляет методы для работы с жанрами в базе данных.
+type GenreRepository struct {
+	DB *gorm.DB // Экземпляр базы данных GORM
+}
+
+// GetGenres получает все жанры, упорядоченные по названию.
+// Иерархия передается через ParentID.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//
+// Возвращает:
+//   - []models.Genre: список жанров.
+//   - error: ошибка, если запрос не удался.
+func (repo *GenreRepository) GetGenres(ctx context.Context) ([]models.Genre, error) {
+	var genres []models.Genre
+	if err := repo.DB.WithContext(ctx).Order("name_key, id").Find(&genres).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve genres. Error: %v\n", err)
+		return nil, err
+	}
+	return genres, nil
+}
+
+// GetGenreByID получает жанр по идентификатору.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID жанра.
+//
+// Возвращает:
+//   - *models.Genre: найденный жанр.
+//   - error: gorm.ErrRecordNotFound, если жанр не найден.
+func (repo *GenreRepository) GetGenreByID(ctx context.Context, id uint) (*models.Genre, error) {
+	var genre models.Genre
+	if err := repo.DB.WithContext(ctx).First(&genre, id).Error; err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+// CreateGenre сохраняет новый жанр.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - genre *models.Genre: жанр для сохранения.
+//
+// Возвращает:
+//   - *models.Genre: сохраненный жанр.
+//   - error: ErrDuplicateGenre, ErrGenreParentNotFound или ошибка сохранения.
+func (repo *GenreRepository) CreateGenre(ctx context.Context, genre *models.Genre) (*models.Genre, error) {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureGenreNameFree(tx, genre.Name, 0); err != nil {
+			return err
+		}
+		if err := ensureGenreParent(tx, 0, genre.ParentID); err != nil {
+			return err
+		}
+		return tx.Create(genre).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to create genre '%s'. Error: %v\n", genre.Name, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully created genre with ID: %d\n", genre.ID)
+	return genre, nil
+}
+
+// UpdateGenre обновляет жанр, в том числе перемещает его в другую ветку иерархии.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - genre *models.Genre: обновленный жанр (с заполненным ID).
+//
+// Возвращает:
+//   - *models.Genre: обновленный жанр.
+//   - error: ErrDuplicateGenre, ErrGenreParentNotFound, ErrGenreCycle или ошибка сохранения.
+func (repo *GenreRepository) UpdateGenre(ctx context.Context, genre *models.Genre) (*models.Genre, error) {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureGenreNameFree(tx, genre.Name, genre.ID); err != nil {
+			return err
+		}
+		if err := ensureGenreParent(tx, genre.ID, genre.ParentID); err != nil {
+			return err
+		}
+		return tx.Save(genre).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to update genre with ID: %d. Error: %v\n", genre.ID, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully updated genre with ID: %d\n", genre.ID)
+	return genre, nil
+}
+
+// DeleteGenre удаляет жанр без поджанров. Связи песен с жанром удаляются каскадно.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID жанра.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если жанр не найден; ErrGenreHasChildren, если у него есть поджанры.
+func (repo *GenreRepository) DeleteGenre(ctx context.Context, id uint) error {
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&models.Genre{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrGenreHasChildren
+		}
+
+		result := tx.Delete(&models.Genre{}, id)
+		if result.Error != nil {
+			log.Printf("ERROR: Failed to delete genre with ID: %d. Error: %v\n", id, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		log.Printf("INFO: Successfully deleted genre with ID: %d\n", id)
+		return nil
+	})
+}
+
+// SetSongGenres заменяет набор жанров песни.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - songID uint: ID песни.
+//   - genreIDs []int: новый набор жанров (пустой — удалить все жанры песни).
+//
+// Возвращает:
+//   - *models.Song: песня с обновленными жанрами и тегами.
+//   - error: gorm.ErrRecordNotFound, если песня не найдена; ErrGenreNotFound, если жанр не существует.
+func (repo *GenreRepository) SetSongGenres(ctx context.Context, songID uint, genreIDs []int) (*models.Song, error) {
+	db := repo.DB.WithContext(ctx)
+	unique := make(map[int]struct{}, len(genreIDs))
+	rows := make([]songGenre, 0, len(genreIDs))
+	for _, genreID := range genreIDs {
+		if _, seen := unique[genreID]; !seen {
+			unique[genreID] = struct{}{}
+			rows = append(rows, songGenre{SongID: int(songID), GenreID: genreID})
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Song{}, songID).Error; err != nil {
+			return err
+		}
+
+		var found int64
+		if err := tx.Model(&models.Genre{}).Where("id IN ?", genreIDsOf(rows)).Count(&found).Error; err != nil {
+			return err
+		}
+		if int(found) != len(rows) {
+			return ErrGenreNotFound
+		}
+
+		if err := tx.Where("song_id = ?", songID).Delete(&songGenre{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to set genres of song with ID: %d. Error: %v\n", songID, err)
+		return nil, err
+	}
+	return getSongWithCategories(db, songID)
+}
+
+// ensureGenreNameFree проверяет, что нормализованное название не занято другим жанром.
+func ensureGenreNameFree(tx *gorm.DB, name string, exceptID int) error {
+	var count int64
+	if err := tx.Model(&models.Genre{}).
+		Where("name_key = ? AND id <> ?", normalize.Name(name), exceptID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDuplicateGenre
+	}
+	return nil
+}
+
+// ensureGenreParent проверяет, что родительский жанр существует и не приводит к циклу в иерархии.
+func ensureGenreParent(tx *gorm.DB, genreID int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&models.Genre{}).Where("id = ?", *parentID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrGenreParentNotFound
+	}
+	if genreID == 0 {
+		return nil
+	}
+
+	// Родитель не может быть самим жанром или одним из его потомков
+	if err := tx.Raw("SELECT COUNT(*) FROM ("+genreSubtreeSQL+") AS descendants WHERE id = ?", genreID, *parentID).
+		Scan(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrGenreCycle
+	}
+	return nil
+}
+
+// genreIDsOf возвращает ID жанров из строк связующей таблицы.
+func genreIDsOf(rows []songGenre) []int {
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.GenreID)
+	}
+	return ids
+}
+
+// getSongWithCategories получает песню вместе с жанрами и тегами.
+func getSongWithCategories(db *gorm.DB, songID uint) (*models.Song, error) {
+	var song models.Song
+	if err := db.Preload("Genres").Preload("Tags").First(&song, songID).Error; err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
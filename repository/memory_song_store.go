@@ -0,0 +1,423 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"music-library/models"
+	"music-library/normalize"
+)
+
+// MemorySongStore — реализация SongStore, хранящая песни в памяти процесса.
+// Используется в тестах обработчиков песен (controllers) вместо базы данных.
+//
+// Отличия от SongRepository: песни не связываются с исполнителями (ArtistID не заполняется),
+// плейлисты не поддерживаются, а фильтр по жанру учитывает только жанры, назначенные песне напрямую.
+type MemorySongStore struct {
+	mu     sync.RWMutex
+	songs  map[int]models.Song
+	nextID int
+}
+
+var _ SongStore = (*MemorySongStore)(nil)
+
+// NewMemorySongStore создает хранилище, заполненное переданными песнями.
+// Песням без ID назначаются новые идентификаторы.
+func NewMemorySongStore(songs ...models.Song) *MemorySongStore {
+	store := &MemorySongStore{songs: make(map[int]models.Song)}
+	for _, song := range songs {
+		store.insert(song)
+	}
+	return store
+}
+
+// SaveSong сохраняет новую песню.
+func (s *MemorySongStore) SaveSong(_ context.Context, song *models.Song) (*models.Song, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prepareSong(song)
+	if _, ok := s.findActive(song.GroupKey, song.SongKey, 0); ok {
+		return nil, ErrDuplicateSong
+	}
+	*song = s.insert(*song)
+	return song, nil
+}
+
+// GetOrCreateSong сохраняет песню или возвращает существующую с той же парой группа+песня.
+func (s *MemorySongStore) GetOrCreateSong(_ context.Context, song *models.Song) (*models.Song, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prepareSong(song)
+	if existing, ok := s.findActive(song.GroupKey, song.SongKey, 0); ok {
+		return &existing, false, nil
+	}
+	*song = s.insert(*song)
+	return song, true, nil
+}
+
+// GetAllSongs возвращает страницу песен.
+func (s *MemorySongStore) GetAllSongs(_ context.Context, filter SongFilter, order SongSort, page int, limit int) ([]models.Song, int64, error) {
+	songs, err := s.list(filter, order)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pageOf(songs, (page-1)*limit, limit), int64(len(songs)), nil
+}
+
+// GetSongsAfter возвращает страницу песен после курсора.
+func (s *MemorySongStore) GetSongsAfter(_ context.Context, filter SongFilter, order SongSort, cursor *SongCursor, limit int) ([]models.Song, int64, error) {
+	songs, err := s.list(filter, order)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := int64(len(songs))
+	if cursor == nil {
+		return pageOf(songs, 0, limit), total, nil
+	}
+
+	position, err := cursor.value()
+	if err != nil {
+		return nil, 0, err
+	}
+	start := sort.Search(len(songs), func(i int) bool {
+		return compareToCursor(songs[i], cursor, position) > 0
+	})
+	return pageOf(songs, start, limit), total, nil
+}
+
+// GetSongByID возвращает активную песню по ID.
+func (s *MemorySongStore) GetSongByID(_ context.Context, id uint) (*models.Song, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song, ok := s.songs[int(id)]
+	if !ok || song.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return &song, nil
+}
+
+// FindSongByGroupAndSong ищет активную песню по нормализованным названиям.
+func (s *MemorySongStore) FindSongByGroupAndSong(_ context.Context, group, song string) (*models.Song, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found, ok := s.findActive(normalize.Fold(group), normalize.Fold(song), 0)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &found, nil
+}
+
+// FindSimilarSongs возвращает песни, похожие на запрошенную (сходство триграмм, как в pg_trgm).
+func (s *MemorySongStore) FindSimilarSongs(_ context.Context, group, song string, limit int) ([]models.SongMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	songs := make([]models.Song, 0, len(s.songs))
+	for _, record := range s.songs {
+		if !record.DeletedAt.Valid {
+			songs = append(songs, record)
+		}
+	}
+	return rankSimilarSongs(songs, normalize.Fold(group)+" "+normalize.Fold(song), limit), nil
+}
+
+// UpdateSong сохраняет изменения активной песни.
+func (s *MemorySongStore) UpdateSong(_ context.Context, song *models.Song) (*models.Song, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.songs[song.ID]
+	if !ok || current.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	prepareSong(song)
+	if _, ok := s.findActive(song.GroupKey, song.SongKey, song.ID); ok {
+		return nil, ErrDuplicateSong
+	}
+
+	song.CreatedAt, song.DeletedAt = current.CreatedAt, current.DeletedAt
+	song.UpdatedAt = time.Now()
+	s.songs[song.ID] = *song
+	return song, nil
+}
+
+// DeleteSong перемещает песню в корзину.
+func (s *MemorySongStore) DeleteSong(_ context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song, ok := s.songs[int(id)]
+	if !ok || song.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	song.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	s.songs[song.ID] = song
+	return nil
+}
+
+// GetTrashedSongs возвращает страницу песен в корзине, начиная с удаленных последними.
+func (s *MemorySongStore) GetTrashedSongs(_ context.Context, page int, limit int) ([]models.Song, int64, error) {
+	s.mu.RLock()
+	trashed := make([]models.Song, 0)
+	for _, song := range s.songs {
+		if song.DeletedAt.Valid {
+			trashed = append(trashed, song)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(trashed, func(i, j int) bool {
+		if !trashed[i].DeletedAt.Time.Equal(trashed[j].DeletedAt.Time) {
+			return trashed[i].DeletedAt.Time.After(trashed[j].DeletedAt.Time)
+		}
+		return trashed[i].ID > trashed[j].ID
+	})
+	return pageOf(trashed, (page-1)*limit, limit), int64(len(trashed)), nil
+}
+
+// RestoreSong восстанавливает песню из корзины.
+func (s *MemorySongStore) RestoreSong(_ context.Context, id uint) (*models.Song, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song, ok := s.songs[int(id)]
+	if !ok || !song.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	if _, ok := s.findActive(song.GroupKey, song.SongKey, song.ID); ok {
+		return nil, ErrDuplicateSong
+	}
+	song.DeletedAt = gorm.DeletedAt{}
+	s.songs[song.ID] = song
+	return &song, nil
+}
+
+// PurgeSong окончательно удаляет песню из корзины.
+func (s *MemorySongStore) PurgeSong(_ context.Context, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song, ok := s.songs[int(id)]
+	if !ok {
+		return ErrNotFound
+	}
+	if !song.DeletedAt.Valid {
+		return ErrSongNotTrashed
+	}
+	delete(s.songs, song.ID)
+	return nil
+}
+
+// PurgeTrashedBefore окончательно удаляет песни, перемещенные в корзину раньше cutoff.
+func (s *MemorySongStore) PurgeTrashedBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, song := range s.songs {
+		if song.DeletedAt.Valid && song.DeletedAt.Time.Before(cutoff) {
+			delete(s.songs, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// insert добавляет песню, назначая ей ID и время создания. Вызывается под блокировкой.
+func (s *MemorySongStore) insert(song models.Song) models.Song {
+	prepareSong(&song)
+	if song.ID == 0 {
+		s.nextID++
+		song.ID = s.nextID
+	} else if song.ID > s.nextID {
+		s.nextID = song.ID
+	}
+	now := time.Now()
+	if song.CreatedAt.IsZero() {
+		song.CreatedAt = now
+	}
+	if song.UpdatedAt.IsZero() {
+		song.UpdatedAt = now
+	}
+	s.songs[song.ID] = song
+	return song
+}
+
+// findActive ищет активную песню с указанными ключами, кроме песни exceptID. Вызывается под блокировкой.
+func (s *MemorySongStore) findActive(groupKey, songKey string, exceptID int) (models.Song, bool) {
+	var found models.Song
+	for _, song := range s.songs {
+		if song.DeletedAt.Valid || song.ID == exceptID || song.GroupKey != groupKey || song.SongKey != songKey {
+			continue
+		}
+		if found.ID == 0 || song.ID < found.ID {
+			found = song
+		}
+	}
+	return found, found.ID != 0
+}
+
+// list возвращает активные песни, удовлетворяющие фильтру, в порядке сортировки.
+func (s *MemorySongStore) list(filter SongFilter, order SongSort) ([]models.Song, error) {
+	field := order.Field
+	if field == "" {
+		field = "id"
+	}
+	if _, ok := SongSortFields[field]; !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	s.mu.RLock()
+	songs := make([]models.Song, 0, len(s.songs))
+	for _, song := range s.songs {
+		if !song.DeletedAt.Valid && filter.matches(song) {
+			songs = append(songs, song)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(songs, func(i, j int) bool {
+		cmp := compareValues(sortValue(songs[i], field), sortValue(songs[j], field))
+		if cmp == 0 {
+			cmp = compareValues(songs[i].ID, songs[j].ID)
+		}
+		if order.Desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	return songs, nil
+}
+
+// prepareSong заполняет производные поля так же, как Song.BeforeSave (без связи с исполнителем).
+func prepareSong(song *models.Song) {
+	if song.Language == "" {
+		song.Language = models.DefaultSearchLanguage
+	}
+	song.GroupKey, song.SongKey = normalize.Fold(song.Group), normalize.Fold(song.Song)
+}
+
+// matches проверяет песню на соответствие фильтру (аналог apply для хранилища в памяти).
+func (f SongFilter) matches(song models.Song) bool {
+	contains := func(value, substring string) bool {
+		return substring == "" || strings.Contains(strings.ToLower(value), strings.ToLower(substring))
+	}
+
+	switch {
+	case f.Group != "" && song.GroupKey != normalize.Fold(f.Group):
+		return false
+	case f.ArtistID != nil && (song.ArtistID == nil || *song.ArtistID != *f.ArtistID):
+		return false
+	case !contains(song.Song, f.Song) || !contains(song.Text, f.Text) || !contains(song.Link, f.Link):
+		return false
+	case f.HasLink != nil && *f.HasLink != (song.Link != ""):
+		return false
+	case f.ReleaseDateFrom != nil && song.ReleaseDate.Before(*f.ReleaseDateFrom):
+		return false
+	case f.ReleaseDateTo != nil && song.ReleaseDate.After(*f.ReleaseDateTo):
+		return false
+	}
+
+	if f.GenreID != nil {
+		found := false
+		for _, genre := range song.Genres {
+			found = found || genre.ID == *f.GenreID
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Tags) > 0 {
+		matched := 0
+		for _, tag := range f.Tags {
+			for _, songTag := range song.Tags {
+				if songTag.Name == tag {
+					matched++
+					break
+				}
+			}
+		}
+		if matched == 0 || (f.AllTags && matched < len(f.Tags)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortValue возвращает значение поля сортировки песни.
+func sortValue(song models.Song, field string) interface{} {
+	switch field {
+	case "group":
+		return song.Group
+	case "song":
+		return song.Song
+	case "text":
+		return song.Text
+	case "link":
+		return song.Link
+	case "release_date":
+		return song.ReleaseDate
+	case "created_at":
+		return song.CreatedAt
+	case "updated_at":
+		return song.UpdatedAt
+	default:
+		return song.ID
+	}
+}
+
+// compareToCursor сравнивает песню с позицией курсора в направлении сортировки:
+// положительное значение означает, что песня идет после курсора.
+func compareToCursor(song models.Song, cursor *SongCursor, position interface{}) int {
+	cmp := 0
+	if cursor.Field != "id" {
+		cmp = compareValues(sortValue(song, cursor.Field), position)
+	}
+	if cmp == 0 {
+		cmp = compareValues(song.ID, cursor.ID)
+	}
+	if cursor.Desc {
+		return -cmp
+	}
+	return cmp
+}
+
+// compareValues сравнивает два значения поля сортировки одного типа.
+func compareValues(a, b interface{}) int {
+	switch left := a.(type) {
+	case string:
+		return strings.Compare(left, b.(string))
+	case time.Time:
+		return left.Compare(b.(time.Time))
+	case int:
+		right := b.(int)
+		switch {
+		case left < right:
+			return -1
+		case left > right:
+			return 1
+		}
+	}
+	return 0
+}
+
+// pageOf возвращает срез списка, начиная с offset, длиной не более limit.
+func pageOf(songs []models.Song, offset, limit int) []models.Song {
+	if offset > len(songs) {
+		offset = len(songs)
+	}
+	end := offset + limit
+	if end > len(songs) {
+		end = len(songs)
+	}
+	return songs[offset:end]
+}
@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"music-library/models"
+	"sort"
+	"time"
+)
+
+// ErrPlaylistSongNotFound возвращается при добавлении в плейлист несуществующей или удаленной песни.
+var ErrPlaylistSongNotFound = errors.New("song not found")
+
+// PlaylistFilter описывает условия отбора плейлистов.
+type PlaylistFilter struct {
+	Owner      string // Владелец (пустая строка — без фильтра)
+	Visibility string // Видимость (пустая строка — без фильтра)
+}
+
+// PlaylistRepository предоставляет методы для работы с плейлистами в базе данных.
+//
+// Позиции записей в плейлисте всегда идут подряд начиная с 1. Все изменения порядка
+// выполняются в транзакции с блокировкой строки плейлиста, поэтому параллельные
+// изменения одного плейлиста не перемешивают позиции.
+//
+// В PostgreSQL уникальность позиции дополнительно проверяет отложенное ограничение
+// uq_playlist_entries_position. В SQLite отложенных ограничений уникальности нет,
+// и база дубликаты позиций не отклоняет: их отсутствие обеспечивают только методы
+// репозитория (блокировку плейлиста там заменяет единственное соединение с базой),
+// поэтому записи плейлиста нельзя изменять в обход PlaylistRepository.
+type PlaylistRepository struct {
+	DB *gorm.DB // Экземпляр базы данных GORM
+}
+
+// GetPlaylists получает список плейлистов без записей, начиная с измененных последними.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - filter PlaylistFilter: условия отбора.
+//   - page int: номер страницы (начиная с 1).
+//   - limit int: количество записей на странице.
+//
+// Возвращает:
+//   - []models.Playlist: список плейлистов.
+//   - int64: общее количество плейлистов, удовлетворяющих фильтру.
+//   - error: ошибка, если запрос не удался.
+func (repo *PlaylistRepository) GetPlaylists(ctx context.Context, filter PlaylistFilter, page int, limit int) ([]models.Playlist, int64, error) {
+	query := func() *gorm.DB {
+		q := repo.DB.WithContext(ctx).Model(&models.Playlist{})
+		if filter.Owner != "" {
+			q = q.Where("owner = ?", filter.Owner)
+		}
+		if filter.Visibility != "" {
+			q = q.Where("visibility = ?", filter.Visibility)
+		}
+		return q
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count playlists. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	var playlists []models.Playlist
+	offset := (page - 1) * limit
+	if err := query().Order("updated_at DESC, id DESC").Limit(limit).Offset(offset).Find(&playlists).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve playlists. Error: %v\n", err)
+		return nil, 0, err
+	}
+	return playlists, total, nil
+}
+
+// GetPlaylistByID получает плейлист вместе с записями по порядку.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID плейлиста.
+//
+// Возвращает:
+//   - *models.Playlist: найденный плейлист.
+//   - error: gorm.ErrRecordNotFound, если плейлист не найден.
+func (repo *PlaylistRepository) GetPlaylistByID(ctx context.Context, id uint) (*models.Playlist, error) {
+	var playlist models.Playlist
+	err := repo.DB.WithContext(ctx).
+		Preload("Entries", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Entries.Song").
+		First(&playlist, id).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range playlist.Entries {
+		playlist.Entries[i].Available = playlist.Entries[i].Song != nil
+	}
+	return &playlist, nil
+}
+
+// CreatePlaylist сохраняет новый пустой плейлист.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - playlist *models.Playlist: плейлист для сохранения.
+//
+// Возвращает:
+//   - *models.Playlist: сохраненный плейлист.
+//   - error: ошибка, если сохранение не удалось.
+func (repo *PlaylistRepository) CreatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error) {
+	if err := repo.DB.WithContext(ctx).Create(playlist).Error; err != nil {
+		log.Printf("ERROR: Failed to create playlist '%s'. Error: %v\n", playlist.Name, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully created playlist with ID: %d\n", playlist.ID)
+	return playlist, nil
+}
+
+// UpdatePlaylist обновляет владельца, название, описание и видимость плейлиста.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - playlist *models.Playlist: обновленный плейлист (с заполненным ID).
+//
+// Возвращает:
+//   - *models.Playlist: обновленный плейлист вместе с записями.
+//   - error: ошибка, если сохранение не удалось.
+func (repo *PlaylistRepository) UpdatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error) {
+	if err := repo.DB.WithContext(ctx).Save(playlist).Error; err != nil {
+		log.Printf("ERROR: Failed to update playlist with ID: %d. Error: %v\n", playlist.ID, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully updated playlist with ID: %d\n", playlist.ID)
+	return repo.GetPlaylistByID(ctx, uint(playlist.ID))
+}
+
+// DeletePlaylist удаляет плейлист вместе с записями.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID плейлиста.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если плейлист не найден, или ошибка удаления.
+func (repo *PlaylistRepository) DeletePlaylist(ctx context.Context, id uint) error {
+	result := repo.DB.WithContext(ctx).Delete(&models.Playlist{}, id)
+	if result.Error != nil {
+		log.Printf("ERROR: Failed to delete playlist with ID: %d. Error: %v\n", id, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	log.Printf("INFO: Successfully deleted playlist with ID: %d\n", id)
+	return nil
+}
+
+// AddEntry добавляет песню в плейлист. Одна песня может встречаться в плейлисте несколько раз.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - playlistID uint: ID плейлиста.
+//   - songID int: ID песни.
+//   - position *int: позиция вставки; nil или позиция за концом списка — добавление в конец.
+//
+// Возвращает:
+//   - *models.PlaylistEntry: созданная запись.
+//   - error: gorm.ErrRecordNotFound, если плейлист не найден; ErrPlaylistSongNotFound, если песня не найдена.
+func (repo *PlaylistRepository) AddEntry(ctx context.Context, playlistID uint, songID int, position *int) (*models.PlaylistEntry, error) {
+	var entry models.PlaylistEntry
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		count, err := lockPlaylist(tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		var song models.Song
+		if err := tx.First(&song, songID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPlaylistSongNotFound
+		} else if err != nil {
+			return err
+		}
+
+		target := int(count) + 1
+		if position != nil && *position < target {
+			target = *position
+			if err := shiftEntries(tx, playlistID, target, int(count), 1); err != nil {
+				return err
+			}
+		}
+
+		entry = models.PlaylistEntry{
+			PlaylistID: int(playlistID),
+			SongID:     songID,
+			Position:   target,
+			AddedAt:    time.Now(),
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+		entry.Song, entry.Available = &song, true
+		return touchPlaylist(tx, playlistID)
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to add song with ID: %d to playlist with ID: %d. Error: %v\n", songID, playlistID, err)
+		return nil, err
+	}
+	log.Printf("INFO: Added song with ID: %d to playlist with ID: %d at position %d\n", songID, playlistID, entry.Position)
+	return &entry, nil
+}
+
+// MoveEntry перемещает запись на новую позицию, сдвигая записи между старой и новой позицией.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - playlistID uint: ID плейлиста.
+//   - entryID uint: ID записи.
+//   - position int: новая позиция; позиция за концом списка означает перемещение в конец.
+//
+// Возвращает:
+//   - *models.Playlist: плейлист с записями в новом порядке.
+//   - error: gorm.ErrRecordNotFound, если плейлист или запись не найдены.
+func (repo *PlaylistRepository) MoveEntry(ctx context.Context, playlistID, entryID uint, position int) (*models.Playlist, error) {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		count, err := lockPlaylist(tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		var entry models.PlaylistEntry
+		if err := tx.Where("playlist_id = ?", playlistID).First(&entry, entryID).Error; err != nil {
+			return err
+		}
+
+		target := min(position, int(count))
+		switch {
+		case target == entry.Position:
+			return nil
+		case target < entry.Position:
+			err = shiftEntries(tx, playlistID, target, entry.Position-1, 1)
+		default:
+			err = shiftEntries(tx, playlistID, entry.Position+1, target, -1)
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&entry).UpdateColumn("position", target).Error; err != nil {
+			return err
+		}
+		return touchPlaylist(tx, playlistID)
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to move entry with ID: %d in playlist with ID: %d. Error: %v\n", entryID, playlistID, err)
+		return nil, err
+	}
+	return repo.GetPlaylistByID(ctx, playlistID)
+}
+
+// RemoveEntry удаляет запись из плейлиста, сдвигая следующие за ней записи.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - playlistID uint: ID плейлиста.
+//   - entryID uint: ID записи.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если плейлист или запись не найдены.
+func (repo *PlaylistRepository) RemoveEntry(ctx context.Context, playlistID, entryID uint) error {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		count, err := lockPlaylist(tx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		var entry models.PlaylistEntry
+		if err := tx.Where("playlist_id = ?", playlistID).First(&entry, entryID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entry).Error; err != nil {
+			return err
+		}
+		if err := shiftEntries(tx, playlistID, entry.Position+1, int(count), -1); err != nil {
+			return err
+		}
+		return touchPlaylist(tx, playlistID)
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to remove entry with ID: %d from playlist with ID: %d. Error: %v\n", entryID, playlistID, err)
+		return err
+	}
+	log.Printf("INFO: Removed entry with ID: %d from playlist with ID: %d\n", entryID, playlistID)
+	return nil
+}
+
+// DuplicatePlaylist создает копию плейлиста со всеми записями (включая записи песен в корзине).
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID исходного плейлиста.
+//   - owner, name, visibility string: параметры копии; пустые значения берутся из исходного плейлиста.
+//
+// Возвращает:
+//   - *models.Playlist: созданная копия вместе с записями.
+//   - error: gorm.ErrRecordNotFound, если исходный плейлист не найден, или ошибка сохранения.
+func (repo *PlaylistRepository) DuplicatePlaylist(ctx context.Context, id uint, owner, name, visibility string) (*models.Playlist, error) {
+	var duplicate models.Playlist
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source models.Playlist
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&source, id).Error; err != nil {
+			return err
+		}
+
+		duplicate = models.Playlist{
+			Owner:       firstNonEmpty(owner, source.Owner),
+			Name:        firstNonEmpty(name, source.Name+" (copy)"),
+			Description: source.Description,
+			Visibility:  firstNonEmpty(visibility, source.Visibility),
+		}
+		if err := tx.Create(&duplicate).Error; err != nil {
+			return err
+		}
+
+		var entries []models.PlaylistEntry
+		if err := tx.Where("playlist_id = ?", id).Order("position").Find(&entries).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		now := time.Now()
+		for i := range entries {
+			entries[i].ID = 0
+			entries[i].PlaylistID = duplicate.ID
+			entries[i].AddedAt = now
+		}
+		return tx.Create(&entries).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to duplicate playlist with ID: %d. Error: %v\n", id, err)
+		return nil, err
+	}
+	log.Printf("INFO: Duplicated playlist with ID: %d as ID: %d\n", id, duplicate.ID)
+	return repo.GetPlaylistByID(ctx, uint(duplicate.ID))
+}
+
+// lockPlaylist блокирует строку плейлиста до конца транзакции и возвращает количество его записей.
+func lockPlaylist(tx *gorm.DB, playlistID uint) (int64, error) {
+	var playlist models.Playlist
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&playlist, playlistID).Error; err != nil {
+		return 0, err
+	}
+	var count int64
+	err := tx.Model(&models.PlaylistEntry{}).Where("playlist_id = ?", playlistID).Count(&count).Error
+	return count, err
+}
+
+// shiftEntries сдвигает позиции записей плейлиста в диапазоне [from, to] на delta.
+func shiftEntries(tx *gorm.DB, playlistID uint, from, to, delta int) error {
+	if from > to {
+		return nil
+	}
+	return tx.Model(&models.PlaylistEntry{}).
+		Where("playlist_id = ? AND position BETWEEN ? AND ?", playlistID, from, to).
+		UpdateColumn("position", gorm.Expr("position + ?", delta)).Error
+}
+
+// touchPlaylist обновляет время изменения плейлиста.
+func touchPlaylist(tx *gorm.DB, playlistID uint) error {
+	return tx.Model(&models.Playlist{}).Where("id = ?", playlistID).UpdateColumn("updated_at", time.Now()).Error
+}
+
+// removeSongsFromPlaylists удаляет записи с указанными песнями из всех плейлистов
+// и заново нумерует оставшиеся записи затронутых плейлистов.
+func removeSongsFromPlaylists(tx *gorm.DB, songIDs []int) error {
+	var playlistIDs []int
+	if err := tx.Model(&models.PlaylistEntry{}).Distinct("playlist_id").
+		Where("song_id IN ?", songIDs).Pluck("playlist_id", &playlistIDs).Error; err != nil {
+		return err
+	}
+	if len(playlistIDs) == 0 {
+		return nil
+	}
+	sort.Ints(playlistIDs) // Единый порядок блокировок исключает взаимоблокировки
+	for _, playlistID := range playlistIDs {
+		if _, err := lockPlaylist(tx, uint(playlistID)); err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("song_id IN ?", songIDs).Delete(&models.PlaylistEntry{}).Error; err != nil {
+		return err
+	}
+
+	for _, playlistID := range playlistIDs {
+		var entries []models.PlaylistEntry
+		if err := tx.Select("id", "position").Where("playlist_id = ?", playlistID).Order("position").Find(&entries).Error; err != nil {
+			return err
+		}
+		for i, entry := range entries {
+			if entry.Position == i+1 {
+				continue
+			}
+			if err := tx.Model(&entry).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		if err := touchPlaylist(tx, uint(playlistID)); err != nil {
+			return err
+		}
+		log.Printf("INFO: Removed purged songs from playlist with ID: %d\n", playlistID)
+	}
+	return nil
+}
+
+// firstNonEmpty возвращает первое непустое значение.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
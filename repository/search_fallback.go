@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"log"
+	"music-library/lyrics"
+	"music-library/models"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// searchWithoutFullText выполняет упрощенный поиск на базах без полнотекстового поиска PostgreSQL (SQLite).
+//
+// Песня находится, если ее группа, название или текст содержат все слова запроса
+// (в режиме phrase — весь запрос целиком) без учета регистра. Стемминга, конфигураций языка
+// и операторов веб-поиска нет. Релевантность — число вхождений слов, причем совпадения
+// в группе и названии весят больше, чем в тексте. Песни перебираются в приложении,
+// поэтому такой поиск подходит только для локальной разработки и тестов.
+func (repo *SearchRepository) searchWithoutFullText(ctx context.Context, query SearchQuery, page int, limit int) ([]models.SearchResult, int64, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, 0, ErrEmptySearchQuery
+	}
+
+	var songs []models.Song
+	if err := repo.DB.WithContext(ctx).Find(&songs).Error; err != nil {
+		log.Printf("ERROR: Failed to search songs. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	results := make([]models.SearchResult, 0)
+	for _, song := range songs {
+		rank, ok := substringRank(song, terms)
+		if !ok {
+			continue
+		}
+		result := models.SearchResult{Song: song, Rank: rank}
+		verses := lyrics.SplitVerses(song.Text)
+		for i, verse := range verses {
+			if snippet, ok := highlightTerms(verse, terms); ok {
+				result.Snippet, result.VerseIndex, result.Verse = snippet, i+1, verse
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Song.ID < results[j].Song.ID
+	})
+
+	total := int64(len(results))
+	start := (page - 1) * limit
+	if start < 0 {
+		start = 0 // Переполнение смещения при огромном номере страницы
+	}
+	if start > len(results) {
+		start = len(results)
+	}
+	end := start + limit
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[start:end], total, nil
+}
+
+// searchTerms разбивает запрос на искомые подстроки в нижнем регистре.
+func searchTerms(query SearchQuery) []string {
+	text := strings.ToLower(query.Text)
+	if query.Mode == SearchModePhrase {
+		if phrase := strings.Join(strings.Fields(text), " "); phrase != "" {
+			return []string{phrase}
+		}
+		return nil
+	}
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// substringRank возвращает релевантность песни и false, если песня содержит не все искомые подстроки.
+func substringRank(song models.Song, terms []string) (float64, bool) {
+	title := strings.ToLower(song.Group + " " + song.Song)
+	text := strings.ToLower(song.Text)
+
+	var rank float64
+	for _, term := range terms {
+		inTitle, inText := strings.Count(title, term), strings.Count(text, term)
+		if inTitle+inText == 0 {
+			return 0, false
+		}
+		rank += float64(inTitle) + 0.1*float64(inText)
+	}
+	return rank, true
+}
+
+// highlightTerms возвращает строку как фрагмент HTML, в котором вхождения искомых подстрок
+// выделены тегами <b>, как в snippetHTML: текст экранируется до расстановки тегов.
+// Возвращает false, если в строке нет ни одного вхождения.
+func highlightTerms(line string, terms []string) (string, bool) {
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		// Смена регистра изменила длину строки в байтах: позиции вхождений не совпадут с исходной строкой
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				return html.EscapeString(line), true
+			}
+		}
+		return "", false
+	}
+
+	var builder strings.Builder
+	found := false
+	plainStart := 0 // Начало участка без совпадений, который еще не записан
+	for i := 0; i < len(line); {
+		matched := ""
+		for _, term := range terms {
+			if strings.HasPrefix(lower[i:], term) && len(term) > len(matched) {
+				matched = term
+			}
+		}
+		if matched == "" {
+			i++
+			continue
+		}
+		builder.WriteString(html.EscapeString(line[plainStart:i]))
+		builder.WriteString(highlightStart + html.EscapeString(line[i:i+len(matched)]) + highlightStop)
+		i += len(matched)
+		plainStart = i
+		found = true
+	}
+	if !found {
+		return "", false
+	}
+	builder.WriteString(html.EscapeString(line[plainStart:]))
+	return builder.String(), true
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"music-library/database"
+	"music-library/models"
+)
+
+func TestSearchWithoutFullTextPages(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=on"), &gorm.Config{TranslateError: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	songs := &SongRepository{DB: db}
+	for _, title := range []string{"Uprising", "Hysteria", "Starlight"} {
+		if _, err := songs.SaveSong(context.Background(), &models.Song{Group: "Muse", Song: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := &SearchRepository{DB: db}
+
+	tests := []struct {
+		name  string
+		page  int
+		limit int
+		want  int
+	}{
+		{name: "first page", page: 1, limit: 2, want: 2},
+		{name: "last page", page: 2, limit: 2, want: 1},
+		{name: "page past the end", page: 3, limit: 2, want: 0},
+		{name: "overflowed offset", page: math.MaxInt/4 + 2, limit: 4, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total, err := repo.Search(context.Background(), SearchQuery{Text: "muse", Mode: SearchModePlain}, tt.page, tt.limit)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if total != 3 || len(results) != tt.want {
+				t.Errorf("Search() total = %d, results = %d, want 3 and %d", total, len(results), tt.want)
+			}
+		})
+	}
+}
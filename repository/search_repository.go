@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"html"
+	"log"
+	"music-library/lyrics"
+	"music-library/models"
+	"strings"
+	"unicode"
+)
+
+// Режимы разбора поискового запроса
+const (
+	SearchModeWeb    = "web"    // Синтаксис веб-поиска: "фраза в кавычках", or, -исключение
+	SearchModePlain  = "plain"  // Все слова запроса
+	SearchModePhrase = "phrase" // Слова запроса идут подряд в указанном порядке
+	SearchModePrefix = "prefix" // Слова запроса как префиксы (поиск по мере ввода)
+)
+
+// Маркеры подсветки совпадений во фрагменте, который возвращается клиенту
+const (
+	highlightStart = "<b>"
+	highlightStop  = "</b>"
+)
+
+// Маркеры подсветки, которые расставляет ts_headline. Символы из области частного
+// использования Unicode не встречаются в текстах песен, поэтому после ts_headline
+// текст фрагмента можно экранировать целиком и только затем заменить их на теги (см. snippetHTML).
+const (
+	headlineStart = "\uE000"
+	headlineStop  = "\uE001"
+)
+
+// headlineOptions — параметры ts_headline: один фрагмент и собственные маркеры подсветки.
+var headlineOptions = fmt.Sprintf(`MaxFragments=1, MaxWords=30, MinWords=10, StartSel="%s", StopSel="%s"`, headlineStart, headlineStop)
+
+// ErrEmptySearchQuery возвращается, если в запросе нет слов для поиска.
+var ErrEmptySearchQuery = errors.New("search query has no words")
+
+// searchQueryFunctions сопоставляет режим поиска с функцией построения tsquery.
+var searchQueryFunctions = map[string]string{
+	SearchModeWeb:    "websearch_to_tsquery",
+	SearchModePlain:  "plainto_tsquery",
+	SearchModePhrase: "phraseto_tsquery",
+	SearchModePrefix: "to_tsquery",
+}
+
+// SearchQuery описывает параметры полнотекстового поиска.
+type SearchQuery struct {
+	Text     string // Текст запроса
+	Mode     string // Режим разбора запроса (SearchMode*)
+	Language string // Конфигурация поиска для стемминга слов запроса
+}
+
+// searchRow — строка результата поискового запроса.
+type searchRow struct {
+	ID       int
+	Rank     float64
+	Headline string
+}
+
+// SearchRepository выполняет полнотекстовый поиск по песням.
+//
+// Поиск ведется по сгенерированной колонке songs.search_vector (группа, название и текст),
+// построенной с конфигурацией языка самой песни. Запрос разбирается и в конфигурации
+// из параметров поиска, и в simple, поэтому находятся как песни на языке запроса
+// (с учетом стемминга), так и песни без указанного языка.
+//
+// На SQLite выполняется упрощенный поиск по подстрокам (см. searchWithoutFullText).
+type SearchRepository struct {
+	DB *gorm.DB // Экземпляр базы данных GORM
+}
+
+// Search ищет песни по запросу, упорядочивая их по релевантности.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - query SearchQuery: параметры поиска.
+//   - page int: номер страницы (начиная с 1).
+//   - limit int: количество результатов на странице.
+//
+// Возвращает:
+//   - []models.SearchResult: найденные песни с фрагментом и номером совпавшего куплета.
+//   - int64: общее количество найденных песен.
+//   - error: ErrEmptySearchQuery, если в запросе нет слов, или ошибка запроса.
+func (repo *SearchRepository) Search(ctx context.Context, query SearchQuery, page int, limit int) ([]models.SearchResult, int64, error) {
+	db := repo.DB.WithContext(ctx)
+	function, ok := searchQueryFunctions[query.Mode]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown search mode: %s", query.Mode)
+	}
+	if !isPostgres(db) {
+		return repo.searchWithoutFullText(ctx, query, page, limit)
+	}
+	text := query.Text
+	if query.Mode == SearchModePrefix {
+		if text = prefixQuery(text); text == "" {
+			return nil, 0, ErrEmptySearchQuery
+		}
+	}
+
+	tsquery := fmt.Sprintf("(%[1]s(?::regconfig, ?) || %[1]s('simple', ?))", function)
+	args := []interface{}{query.Language, text, text}
+
+	var total int64
+	if err := db.Raw(
+		"SELECT COUNT(*) FROM songs WHERE deleted_at IS NULL AND search_vector @@ "+tsquery, args...,
+	).Scan(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count search results. Error: %v\n", err)
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.SearchResult{}, 0, nil
+	}
+
+	var rows []searchRow
+	offset := (page - 1) * limit
+	if err := db.Raw(`WITH q AS (SELECT `+tsquery+` AS query)
+		SELECT s.id, ts_rank(s.search_vector, q.query) AS rank,
+		       ts_headline(s.search_language, s.text, q.query, ?) AS headline
+		FROM songs s, q
+		WHERE s.deleted_at IS NULL AND s.search_vector @@ q.query
+		ORDER BY rank DESC, s.id
+		LIMIT ? OFFSET ?`, append(args, headlineOptions, limit, offset)...,
+	).Scan(&rows).Error; err != nil {
+		log.Printf("ERROR: Failed to search songs. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	var songs []models.Song
+	if err := db.Where("id IN ?", ids).Find(&songs).Error; err != nil {
+		return nil, 0, err
+	}
+	songsByID := make(map[int]models.Song, len(songs))
+	for _, song := range songs {
+		songsByID[song.ID] = song
+	}
+
+	results := make([]models.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		song, ok := songsByID[row.ID]
+		if !ok {
+			continue // Песня удалена между запросами
+		}
+		result := models.SearchResult{Song: song, Rank: row.Rank}
+		if strings.Contains(row.Headline, headlineStart) {
+			result.Snippet = snippetHTML(row.Headline)
+			verses := lyrics.SplitVerses(song.Text)
+			if index := lyrics.VerseContaining(verses, highlightedLine(row.Headline)); index > 0 {
+				result.VerseIndex, result.Verse = index, verses[index-1]
+			}
+		}
+		results = append(results, result)
+	}
+	return results, total, nil
+}
+
+// prefixQuery строит tsquery, в котором каждое слово запроса является префиксом: "hel wor" -> "hel:* & wor:*".
+// Символы, имеющие значение в синтаксисе tsquery, отбрасываются.
+func prefixQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// snippetHTML превращает результат ts_headline во фрагмент HTML: текст песни экранируется,
+// а маркеры ts_headline заменяются тегами <b>. Теги и сущности HTML из текста песни
+// возвращаются клиенту как текст и не могут внедрить разметку.
+func snippetHTML(headline string) string {
+	return strings.NewReplacer(headlineStart, highlightStart, headlineStop, highlightStop).Replace(html.EscapeString(headline))
+}
+
+// highlightedLine возвращает строку фрагмента ts_headline с первым подсвеченным совпадением
+// (без маркеров подсветки и без экранирования).
+func highlightedLine(headline string) string {
+	start := strings.Index(headline, headlineStart)
+	if start < 0 {
+		return ""
+	}
+	lineStart := strings.LastIndex(headline[:start], "\n") + 1
+	lineEnd := strings.Index(headline[start:], "\n")
+	line := headline[lineStart:]
+	if lineEnd >= 0 {
+		line = headline[lineStart : start+lineEnd]
+	}
+	return strings.NewReplacer(headlineStart, "", headlineStop, "").Replace(line)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import "testing"
+
+func TestSnippetHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		want     string
+	}{
+		{name: "plain", headline: "is this the " + headlineStart + "real" + headlineStop + " life", want: "is this the <b>real</b> life"},
+		{
+			name:     "markup in text is escaped",
+			headline: `<script>alert(1)</script> ` + headlineStart + "rock" + headlineStop + ` & "roll"`,
+			want:     `&lt;script&gt;alert(1)&lt;/script&gt; <b>rock</b> &amp; &#34;roll&#34;`,
+		},
+		{name: "bold tags from text stay text", headline: "<b>" + headlineStart + "x" + headlineStop + "</b>", want: "&lt;b&gt;<b>x</b>&lt;/b&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippetHTML(tt.headline); got != tt.want {
+				t.Errorf("snippetHTML(%q) = %q, want %q", tt.headline, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightedLine(t *testing.T) {
+	headline := "first line\nsecond <" + headlineStart + "match" + headlineStop + ">\nthird"
+	if got, want := highlightedLine(headline), "second <match>"; got != want {
+		t.Errorf("highlightedLine() = %q, want %q", got, want)
+	}
+	if got := highlightedLine("no highlight"); got != "" {
+		t.Errorf("highlightedLine() = %q, want empty", got)
+	}
+}
+
+func TestHighlightTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		terms []string
+		want  string
+		found bool
+	}{
+		{name: "match", line: "Rock and Roll", terms: []string{"roll"}, want: "Rock and <b>Roll</b>", found: true},
+		{name: "longest term wins", line: "rolling", terms: []string{"roll", "rolling"}, want: "<b>rolling</b>", found: true},
+		{name: "markup is escaped", line: `<img src=x onerror="a()"> rock & roll`, terms: []string{"rock"}, want: `&lt;img src=x onerror=&#34;a()&#34;&gt; <b>rock</b> &amp; roll`, found: true},
+		{name: "term with markup", line: "a <b> c", terms: []string{"<b>"}, want: "a <b>&lt;b&gt;</b> c", found: true},
+		{name: "length changes with case", line: "İstanbul <i>", terms: []string{"stanbul"}, want: "İstanbul &lt;i&gt;", found: true},
+		{name: "no match", line: "<i>quiet</i>", terms: []string{"loud"}, want: "", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := highlightTerms(tt.line, tt.terms)
+			if got != tt.want || found != tt.found {
+				t.Errorf("highlightTerms(%q) = %q, %v, want %q, %v", tt.line, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"music-library/models"
+)
+
+// ErrInvalidCursor возвращается, если курсор поврежден или не соответствует сортировке запроса.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SongCursor — позиция в отсортированном списке песен для keyset-пагинации.
+// Хранит ключ сортировки последней выданной песни и ее ID.
+type SongCursor struct {
+	Field string `json:"f"`           // Поле сортировки
+	Desc  bool   `json:"d,omitempty"` // Направление сортировки
+	Value string `json:"v,omitempty"` // Значение поля сортировки у последней песни
+	ID    int    `json:"id"`          // ID последней песни
+}
+
+// Encode возвращает непрозрачный токен курсора для передачи клиенту.
+func (cur SongCursor) Encode() string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeSongCursor разбирает токен курсора и проверяет, что он выдан для той же сортировки.
+//
+// Принимает:
+//   - token string: токен, полученный в поле next_cursor.
+//   - sort SongSort: сортировка текущего запроса.
+//
+// Возвращает:
+//   - *SongCursor: разобранный курсор.
+//   - error: ErrInvalidCursor, если токен некорректен.
+func DecodeSongCursor(token string, sort SongSort) (*SongCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cur SongCursor
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	field := sort.Field
+	if field == "" {
+		field = "id"
+	}
+	if cur.Field != field || cur.Desc != sort.Desc {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort order", ErrInvalidCursor)
+	}
+	if _, err := cur.value(); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cur, nil
+}
+
+// NewSongCursor строит курсор, указывающий на переданную песню.
+// Используется для формирования next_cursor по последней песне страницы.
+func NewSongCursor(song models.Song, sort SongSort) SongCursor {
+	cur := SongCursor{Field: sort.Field, Desc: sort.Desc, ID: song.ID}
+	if cur.Field == "" {
+		cur.Field = "id"
+	}
+
+	switch cur.Field {
+	case "group":
+		cur.Value = song.Group
+	case "song":
+		cur.Value = song.Song
+	case "text":
+		cur.Value = song.Text
+	case "link":
+		cur.Value = song.Link
+	case "release_date":
+		cur.Value = song.ReleaseDate.Format(time.RFC3339Nano)
+	case "created_at":
+		cur.Value = song.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		cur.Value = song.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	return cur
+}
+
+// value возвращает значение ключа сортировки в типе, пригодном для сравнения в SQL.
+func (cur SongCursor) value() (interface{}, error) {
+	switch cur.Field {
+	case "id":
+		return nil, nil
+	case "release_date", "created_at", "updated_at":
+		return time.Parse(time.RFC3339Nano, cur.Value)
+	default:
+		if _, ok := SongSortFields[cur.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field: %s", cur.Field)
+		}
+		return cur.Value, nil
+	}
+}
+
+// apply ограничивает запрос песнями, идущими после курсора в порядке сортировки.
+func (cur SongCursor) apply(query *gorm.DB) (*gorm.DB, error) {
+	op := ">"
+	if cur.Desc {
+		op = "<"
+	}
+	if cur.Field == "id" {
+		return query.Where("id "+op+" ?", cur.ID), nil
+	}
+
+	value, err := cur.value()
+	if err != nil {
+		return nil, err
+	}
+	column := SongSortFields[cur.Field]
+	condition := fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, op)
+	return query.Where(condition, value, value, cur.ID), nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"music-library/models"
+)
+
+func TestSongCursorRoundTrip(t *testing.T) {
+	song := models.Song{
+		ID:          42,
+		Group:       "Muse",
+		Song:        "Uprising",
+		ReleaseDate: time.Date(2009, 9, 7, 0, 0, 0, 0, time.UTC),
+		CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC),
+	}
+	tests := []struct {
+		sort      SongSort
+		wantField string
+		wantValue string
+	}{
+		{sort: SongSort{}, wantField: "id", wantValue: ""},
+		{sort: SongSort{Field: "song"}, wantField: "song", wantValue: "Uprising"},
+		{sort: SongSort{Field: "group", Desc: true}, wantField: "group", wantValue: "Muse"},
+		{sort: SongSort{Field: "release_date"}, wantField: "release_date", wantValue: "2009-09-07T00:00:00Z"},
+		{sort: SongSort{Field: "created_at", Desc: true}, wantField: "created_at", wantValue: "2024-01-02T03:04:05.123456789Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantField, func(t *testing.T) {
+			cursor := NewSongCursor(song, tt.sort)
+			if cursor.Field != tt.wantField || cursor.Value != tt.wantValue || cursor.ID != 42 || cursor.Desc != tt.sort.Desc {
+				t.Fatalf("NewSongCursor() = %+v", cursor)
+			}
+
+			decoded, err := DecodeSongCursor(cursor.Encode(), tt.sort)
+			if err != nil {
+				t.Fatalf("DecodeSongCursor() error = %v", err)
+			}
+			if *decoded != cursor {
+				t.Errorf("DecodeSongCursor() = %+v, want %+v", *decoded, cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeSongCursorErrors(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	valid := SongCursor{Field: "song", Value: "Uprising", ID: 1}.Encode()
+
+	tests := []struct {
+		name  string
+		token string
+		sort  SongSort
+	}{
+		{name: "not base64", token: "%%%", sort: SongSort{Field: "song"}},
+		{name: "padded base64", token: base64.URLEncoding.EncodeToString([]byte(`{"f":"id","id":1}`)), sort: SongSort{}},
+		{name: "not json", token: encode("song:1"), sort: SongSort{Field: "song"}},
+		{name: "other field", token: valid, sort: SongSort{Field: "group"}},
+		{name: "other direction", token: valid, sort: SongSort{Field: "song", Desc: true}},
+		{name: "bad time", token: encode(`{"f":"release_date","v":"yesterday","id":1}`), sort: SongSort{Field: "release_date"}},
+		{name: "unknown field", token: encode(`{"f":"password","v":"x","id":1}`), sort: SongSort{Field: "password"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeSongCursor(tt.token, tt.sort); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeSongCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"music-library/normalize"
+)
+
+// SongFilter описывает условия отбора песен.
+// Пустые поля не участвуют в фильтрации.
+type SongFilter struct {
+	Group           string     // Совпадение названия группы (без учета регистра, пробелов и диакритики)
+	ArtistID        *int       // Исполнитель
+	Song            string     // Подстрока в названии песни
+	Text            string     // Подстрока в тексте песни
+	Link            string     // Подстрока в ссылке
+	HasLink         *bool      // Наличие ссылки
+	ReleaseDateFrom *time.Time // Дата релиза не раньше указанной
+	ReleaseDateTo   *time.Time // Дата релиза не позже указанной
+	GenreID         *int       // Жанр, включая все его поджанры
+	Tags            []string   // Теги (нормализованные имена)
+	AllTags         bool       // Песня должна иметь все теги из Tags, а не хотя бы один
+}
+
+// SongSort описывает порядок сортировки списка песен.
+type SongSort struct {
+	Field string // Поле сортировки (см. SongSortFields)
+	Desc  bool   // Сортировка по убыванию
+}
+
+// SongSortFields сопоставляет имена полей из API с колонками таблицы songs.
+var SongSortFields = map[string]string{
+	"id":           "id",
+	"group":        "\"group\"",
+	"song":         "song",
+	"text":         "text",
+	"release_date": "release_date",
+	"link":         "link",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
+// apply добавляет условия фильтра к запросу.
+func (f SongFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Group != "" {
+		query = query.Where("group_key = ?", normalize.Fold(f.Group))
+	}
+	if f.ArtistID != nil {
+		query = query.Where("artist_id = ?", *f.ArtistID)
+	}
+	if f.Song != "" {
+		query = query.Where("LOWER(song) LIKE ? ESCAPE '\\'", containsPattern(f.Song))
+	}
+	if f.Text != "" {
+		query = query.Where("LOWER(text) LIKE ? ESCAPE '\\'", containsPattern(f.Text))
+	}
+	if f.Link != "" {
+		query = query.Where("LOWER(link) LIKE ? ESCAPE '\\'", containsPattern(f.Link))
+	}
+	if f.HasLink != nil {
+		if *f.HasLink {
+			query = query.Where("link IS NOT NULL AND link <> ''")
+		} else {
+			query = query.Where("link IS NULL OR link = ''")
+		}
+	}
+	if f.ReleaseDateFrom != nil {
+		query = query.Where("release_date >= ?", *f.ReleaseDateFrom)
+	}
+	if f.ReleaseDateTo != nil {
+		query = query.Where("release_date <= ?", *f.ReleaseDateTo)
+	}
+	if f.GenreID != nil {
+		query = query.Where("id IN (SELECT song_id FROM song_genres WHERE genre_id IN ("+genreSubtreeSQL+"))", *f.GenreID)
+	}
+	if len(f.Tags) > 0 {
+		if f.AllTags {
+			query = query.Where(`id IN (SELECT st.song_id FROM song_tags st JOIN tags t ON t.id = st.tag_id
+				WHERE t.name IN ? GROUP BY st.song_id HAVING COUNT(DISTINCT t.id) = ?)`, f.Tags, len(f.Tags))
+		} else {
+			query = query.Where(`id IN (SELECT st.song_id FROM song_tags st JOIN tags t ON t.id = st.tag_id
+				WHERE t.name IN ?)`, f.Tags)
+		}
+	}
+	return query
+}
+
+// orderClause возвращает выражение ORDER BY для сортировки.
+// ID добавляется вторым ключом, чтобы порядок был детерминированным.
+func (s SongSort) orderClause() (string, error) {
+	field := s.Field
+	if field == "" {
+		field = "id"
+	}
+	column, ok := SongSortFields[field]
+	if !ok {
+		return "", fmt.Errorf("unknown sort field: %s", field)
+	}
+
+	direction := "ASC"
+	if s.Desc {
+		direction = "DESC"
+	}
+	if column == "id" {
+		return "id " + direction, nil
+	}
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction), nil
+}
+
+// containsPattern строит LIKE-шаблон для поиска подстроки без учета регистра.
+func containsPattern(value string) string {
+	escaped := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(strings.ToLower(value))
+	return "%" + escaped + "%"
+}
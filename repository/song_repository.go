@@ -1,12 +1,23 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log"
 	"music-library/models"
+	"music-library/normalize"
+	"time"
 )
 
-// SongRepository предоставляет методы для работы с песнями в базе данных.
+// ErrDuplicateSong возвращается, если песня с такой же парой группа+песня уже существует.
+var ErrDuplicateSong = errors.New("song with the same group and title already exists")
+
+// ErrSongNotTrashed возвращается при попытке окончательно удалить песню, которая не находится в корзине.
+var ErrSongNotTrashed = errors.New("song is not in trash")
+
+// SongRepository — реализация SongStore поверх базы данных PostgreSQL или SQLite (GORM).
 type SongRepository struct {
 	DB *gorm.DB // Экземпляр базы данных GORM
 }
@@ -14,54 +25,155 @@ type SongRepository struct {
 // SaveSong сохраняет песню в базе данных.
 //
 // Принимает:
+//   - ctx context.Context: контекст запроса.
 //   - song *models.Song: объект песни для сохранения.
 //
 // Возвращает:
 //   - *models.Song: сохраненный объект песни.
-//   - error: ошибка, если сохранение не удалось.
-func (repo *SongRepository) SaveSong(song *models.Song) (*models.Song, error) {
-	if err := repo.DB.Create(song).Error; err != nil {
+//   - error: ErrDuplicateSong, если активная песня с той же парой группа+песня уже есть, или ошибка сохранения.
+func (repo *SongRepository) SaveSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	if err := repo.DB.WithContext(ctx).Create(song).Error; err != nil {
 		log.Printf("ERROR: Failed to save song. Error: %v\n", err)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrDuplicateSong
+		}
 		return nil, err
 	}
 	log.Printf("INFO: Successfully saved song with ID: %d\n", song.ID)
 	return song, nil
 }
 
-// GetAllSongs получает список всех песен с пагинацией.
+// GetOrCreateSong сохраняет песню, если активной песни с той же нормализованной парой
+// группа+песня еще нет, иначе возвращает существующую. Вставка выполняется через
+// INSERT ... ON CONFLICT DO NOTHING по уникальному индексу, поэтому безопасна при параллельных запросах.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - song *models.Song: песня для сохранения.
+//
+// Возвращает:
+//   - *models.Song: сохраненная или уже существующая песня.
+//   - bool: true, если песня была создана.
+//   - error: ошибка, если сохранение не удалось.
+func (repo *SongRepository) GetOrCreateSong(ctx context.Context, song *models.Song) (*models.Song, bool, error) {
+	result := repo.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "group_key"}, {Name: "song_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(song)
+	if result.Error != nil {
+		log.Printf("ERROR: Failed to save song. Error: %v\n", result.Error)
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("INFO: Successfully saved song with ID: %d\n", song.ID)
+		return song, true, nil
+	}
+
+	existing, err := repo.FindSongByGroupAndSong(ctx, song.Group, song.Song)
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve existing song '%s' by '%s'. Error: %v\n", song.Song, song.Group, err)
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+// GetAllSongs получает список песен с фильтрацией, сортировкой и пагинацией.
 //
 // Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - filter SongFilter: условия отбора песен.
+//   - sort SongSort: порядок сортировки.
 //   - page int: номер страницы (начиная с 1).
 //   - limit int: количество записей на странице.
 //
 // Возвращает:
 //   - []models.Song: список песен.
+//   - int64: общее количество песен, удовлетворяющих фильтру.
 //   - error: ошибка, если запрос не удался.
-func (repo *SongRepository) GetAllSongs(page int, limit int) ([]models.Song, error) {
+func (repo *SongRepository) GetAllSongs(ctx context.Context, filter SongFilter, sort SongSort, page int, limit int) ([]models.Song, int64, error) {
 	log.Printf("INFO: Retrieving all songs. Page: %d, Limit: %d\n", page, limit)
+	order, err := sort.orderClause()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db := repo.DB.WithContext(ctx)
+	var total int64
+	if err := filter.apply(db.Model(&models.Song{})).Count(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count songs. Error: %v\n", err)
+		return nil, 0, err
+	}
+
 	var songs []models.Song
 	offset := (page - 1) * limit
 
-	if err := repo.DB.Limit(limit).Offset(offset).Find(&songs).Error; err != nil {
+	if err := filter.apply(db).Preload("Genres").Preload("Tags").Order(order).Limit(limit).Offset(offset).Find(&songs).Error; err != nil {
 		log.Printf("ERROR: Failed to retrieve songs. Page: %d, Limit: %d, Error: %v\n", page, limit, err)
-		return nil, err
+		return nil, 0, err
 	}
-	log.Printf("INFO: Successfully retrieved %d songs.\n", len(songs))
-	return songs, nil
+	log.Printf("INFO: Successfully retrieved %d of %d songs.\n", len(songs), total)
+	return songs, total, nil
+}
+
+// GetSongsAfter получает страницу песен, следующих за курсором (keyset-пагинация).
+// В отличие от GetAllSongs не использует OFFSET, поэтому не пропускает и не дублирует
+// записи при параллельных вставках.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - filter SongFilter: условия отбора песен.
+//   - sort SongSort: порядок сортировки.
+//   - cursor *SongCursor: позиция, после которой начинается страница (nil — с начала списка).
+//   - limit int: количество записей на странице.
+//
+// Возвращает:
+//   - []models.Song: список песен.
+//   - int64: общее количество песен, удовлетворяющих фильтру.
+//   - error: ошибка, если запрос не удался.
+func (repo *SongRepository) GetSongsAfter(ctx context.Context, filter SongFilter, sort SongSort, cursor *SongCursor, limit int) ([]models.Song, int64, error) {
+	log.Printf("INFO: Retrieving songs by cursor. Limit: %d\n", limit)
+	order, err := sort.orderClause()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db := repo.DB.WithContext(ctx)
+	var total int64
+	if err := filter.apply(db.Model(&models.Song{})).Count(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count songs. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	query := filter.apply(db)
+	if cursor != nil {
+		if query, err = cursor.apply(query); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var songs []models.Song
+	if err := query.Preload("Genres").Preload("Tags").Order(order).Limit(limit).Find(&songs).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve songs by cursor. Limit: %d, Error: %v\n", limit, err)
+		return nil, 0, err
+	}
+	log.Printf("INFO: Successfully retrieved %d of %d songs.\n", len(songs), total)
+	return songs, total, nil
 }
 
 // GetSongByID получает песню по ее уникальному идентификатору.
 //
 // Принимает:
+//   - ctx context.Context: контекст запроса.
 //   - id uint: ID песни.
 //
 // Возвращает:
 //   - *models.Song: найденная песня.
 //   - error: ошибка, если песня не найдена.
-func (repo *SongRepository) GetSongByID(id uint) (*models.Song, error) {
+func (repo *SongRepository) GetSongByID(ctx context.Context, id uint) (*models.Song, error) {
 	log.Printf("INFO: Retrieving song with ID: %d\n", id)
 	var song models.Song
-	if err := repo.DB.First(&song, id).Error; err != nil {
+	if err := repo.DB.WithContext(ctx).First(&song, id).Error; err != nil {
 		log.Printf("ERROR: Failed to retrieve song with ID: %d, Error: %v\n", id, err)
 		return nil, err
 	}
@@ -69,37 +181,261 @@ func (repo *SongRepository) GetSongByID(id uint) (*models.Song, error) {
 	return &song, nil
 }
 
+// FindSongByGroupAndSong ищет песню по названию группы и названию песни
+// без учета регистра, лишних пробелов и диакритических знаков.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - group string: название группы.
+//   - song string: название песни.
+//
+// Возвращает:
+//   - *models.Song: найденная песня.
+//   - error: gorm.ErrRecordNotFound, если песня не найдена, или ошибка запроса.
+func (repo *SongRepository) FindSongByGroupAndSong(ctx context.Context, group, song string) (*models.Song, error) {
+	var record models.Song
+	if err := repo.DB.WithContext(ctx).Where("group_key = ? AND song_key = ?", normalize.Fold(group), normalize.Fold(song)).
+		Order("id").First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindSimilarSongs ищет песни, названия которых похожи на запрошенные (сходство триграмм pg_trgm).
+// На SQLite сходство вычисляется в приложении перебором всех активных песен.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - group string: название группы.
+//   - song string: название песни.
+//   - limit int: максимальное количество результатов.
+//
+// Возвращает:
+//   - []models.SongMatch: похожие песни, начиная с самой похожей.
+//   - error: ошибка, если запрос не удался.
+func (repo *SongRepository) FindSimilarSongs(ctx context.Context, group, song string, limit int) ([]models.SongMatch, error) {
+	key := normalize.Fold(group) + " " + normalize.Fold(song)
+	if !isPostgres(repo.DB) {
+		var songs []models.Song
+		if err := repo.DB.WithContext(ctx).Select("id", "group", "song", "group_key", "song_key").Find(&songs).Error; err != nil {
+			log.Printf("ERROR: Failed to find songs similar to '%s' by '%s'. Error: %v\n", song, group, err)
+			return nil, err
+		}
+		return rankSimilarSongs(songs, key, limit), nil
+	}
+
+	var matches []models.SongMatch
+	if err := repo.DB.WithContext(ctx).Raw(`SELECT id, "group", song, similarity(group_key || ' ' || song_key, ?) AS similarity
+		FROM songs
+		WHERE deleted_at IS NULL
+		ORDER BY (group_key || ' ' || song_key) <-> ?, id
+		LIMIT ?`, key, key, limit).Scan(&matches).Error; err != nil {
+		log.Printf("ERROR: Failed to find songs similar to '%s' by '%s'. Error: %v\n", song, group, err)
+		return nil, err
+	}
+	return matches, nil
+}
+
 // UpdateSong обновляет существующую песню в базе данных.
 //
 // Принимает:
+//   - ctx context.Context: контекст запроса.
 //   - song *models.Song: обновленный объект песни.
 //
 // Возвращает:
 //   - *models.Song: обновленный объект песни.
-//   - error: ошибка, если обновление не удалось.
-func (repo *SongRepository) UpdateSong(song *models.Song) (*models.Song, error) {
+//   - error: ErrDuplicateSong, если изменение нарушает уникальность песни, или ошибка обновления.
+func (repo *SongRepository) UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
 	log.Printf("INFO: Updating song with ID: %d\n", song.ID)
-	if err := repo.DB.Save(song).Error; err != nil {
+	if err := repo.DB.WithContext(ctx).Save(song).Error; err != nil {
 		log.Printf("ERROR: Failed to update song with ID: %d, Error: %v\n", song.ID, err)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrDuplicateSong
+		}
 		return nil, err
 	}
 	log.Printf("INFO: Successfully updated song with ID: %d\n", song.ID)
 	return song, nil
 }
 
-// DeleteSong удаляет песню по ее уникальному идентификатору.
+// DeleteSong перемещает песню в корзину (мягкое удаление).
+// Записи с песней в плейлистах сохраняют позиции, но помечаются как недоступные до восстановления.
 //
 // Принимает:
+//   - ctx context.Context: контекст запроса.
 //   - id uint: ID песни.
 //
 // Возвращает:
-//   - error: ошибка, если удаление не удалось.
-func (repo *SongRepository) DeleteSong(id uint) error {
+//   - error: gorm.ErrRecordNotFound, если активной песни с таким ID нет, или ошибка удаления.
+func (repo *SongRepository) DeleteSong(ctx context.Context, id uint) error {
 	log.Printf("INFO: Deleting song with ID: %d\n", id)
-	if err := repo.DB.Delete(&models.Song{}, id).Error; err != nil {
-		log.Printf("ERROR: Failed to delete song with ID: %d, Error: %v\n", id, err)
-		return err
+	result := repo.DB.WithContext(ctx).Delete(&models.Song{}, id)
+	if result.Error != nil {
+		log.Printf("ERROR: Failed to delete song with ID: %d, Error: %v\n", id, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
 	}
-	log.Printf("INFO: Successfully deleted song with ID: %d\n", id)
+	log.Printf("INFO: Successfully moved song with ID: %d to trash\n", id)
 	return nil
 }
+
+// GetTrashedSongs получает список песен в корзине, начиная с удаленных последними.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - page int: номер страницы (начиная с 1).
+//   - limit int: количество записей на странице.
+//
+// Возвращает:
+//   - []models.Song: список удаленных песен.
+//   - int64: общее количество песен в корзине.
+//   - error: ошибка, если запрос не удался.
+func (repo *SongRepository) GetTrashedSongs(ctx context.Context, page int, limit int) ([]models.Song, int64, error) {
+	trashed := func() *gorm.DB {
+		return repo.DB.WithContext(ctx).Unscoped().Model(&models.Song{}).Where("deleted_at IS NOT NULL")
+	}
+
+	var total int64
+	if err := trashed().Count(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count trashed songs. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	var songs []models.Song
+	offset := (page - 1) * limit
+	if err := trashed().Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&songs).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve trashed songs. Error: %v\n", err)
+		return nil, 0, err
+	}
+	return songs, total, nil
+}
+
+// RestoreSong восстанавливает песню из корзины.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID песни.
+//
+// Возвращает:
+//   - *models.Song: восстановленная песня.
+//   - error: gorm.ErrRecordNotFound, если песни нет в корзине; ErrDuplicateSong, если уже есть
+//     активная песня с той же парой группа+песня.
+func (repo *SongRepository) RestoreSong(ctx context.Context, id uint) (*models.Song, error) {
+	log.Printf("INFO: Restoring song with ID: %d\n", id)
+	var song models.Song
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&song, id).Error; err != nil {
+			return err
+		}
+
+		// Не допускаем появления двух активных песен с одинаковой парой группа+песня
+		var duplicates int64
+		if err := tx.Model(&models.Song{}).Where("group_key = ? AND song_key = ?", normalize.Fold(song.Group), normalize.Fold(song.Song)).Count(&duplicates).Error; err != nil {
+			return err
+		}
+		if duplicates > 0 {
+			return ErrDuplicateSong
+		}
+
+		// Если позицию песни в альбоме за время нахождения в корзине заняла другая песня,
+		// песня остается в альбоме, но без номера диска и трека
+		if song.AlbumID != nil && song.TrackNumber != nil {
+			var occupied int64
+			if err := tx.Model(&models.Song{}).
+				Where("album_id = ? AND disc_number = ? AND track_number = ?", *song.AlbumID, song.DiscNumber, *song.TrackNumber).
+				Count(&occupied).Error; err != nil {
+				return err
+			}
+			if occupied > 0 {
+				song.TrackNumber, song.DiscNumber = nil, nil
+			}
+		}
+
+		song.DeletedAt = gorm.DeletedAt{}
+		err := tx.Unscoped().Model(&song).UpdateColumns(map[string]interface{}{
+			"deleted_at":   nil,
+			"track_number": song.TrackNumber,
+			"disc_number":  song.DiscNumber,
+		}).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrDuplicateSong // Такую же песню успели добавить параллельно
+		}
+		return err
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to restore song with ID: %d, Error: %v\n", id, err)
+		return nil, err
+	}
+	log.Printf("INFO: Successfully restored song with ID: %d\n", id)
+	return &song, nil
+}
+
+// PurgeSong окончательно удаляет песню, находящуюся в корзине.
+// Песня удаляется из всех плейлистов, позиции остальных записей сдвигаются.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID песни.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если песни нет; ErrSongNotTrashed, если песня не в корзине.
+func (repo *SongRepository) PurgeSong(ctx context.Context, id uint) error {
+	log.Printf("INFO: Purging song with ID: %d\n", id)
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var song models.Song
+		if err := tx.Unscoped().First(&song, id).Error; err != nil {
+			return err
+		}
+		if !song.DeletedAt.Valid {
+			return ErrSongNotTrashed
+		}
+		if err := removeSongsFromPlaylists(tx, []int{song.ID}); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&song).Error; err != nil {
+			log.Printf("ERROR: Failed to purge song with ID: %d, Error: %v\n", id, err)
+			return err
+		}
+		log.Printf("INFO: Successfully purged song with ID: %d\n", id)
+		return nil
+	})
+}
+
+// PurgeTrashedBefore окончательно удаляет песни, перемещенные в корзину раньше указанного момента.
+// Песни удаляются из всех плейлистов, позиции остальных записей сдвигаются.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - cutoff time.Time: граница времени удаления.
+//
+// Возвращает:
+//   - int64: количество удаленных песен.
+//   - error: ошибка, если удаление не удалось.
+func (repo *SongRepository) PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var purged int64
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []int
+		if err := tx.Unscoped().Model(&models.Song{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := removeSongsFromPlaylists(tx, ids); err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Song{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to purge trashed songs. Error: %v\n", err)
+		return 0, err
+	}
+	return purged, nil
+}
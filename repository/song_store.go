@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"music-library/models"
+)
+
+// ErrNotFound возвращается хранилищами, если запись не найдена.
+// Совпадает с gorm.ErrRecordNotFound, поэтому проверки errors.Is работают для любой реализации.
+var ErrNotFound = gorm.ErrRecordNotFound
+
+// SongStore — хранилище песен, через которое работают обработчики HTTP и фоновые задачи.
+//
+// Реализации: SongRepository (PostgreSQL или SQLite через GORM) и MemorySongStore —
+// хранилище в памяти для тестов обработчиков (см. controllers/song_controller_test.go).
+// Все методы принимают контекст запроса; отсутствие записи обозначается ErrNotFound.
+type SongStore interface {
+	// SaveSong сохраняет новую песню; ErrDuplicateSong, если такая активная песня уже есть.
+	SaveSong(ctx context.Context, song *models.Song) (*models.Song, error)
+	// GetOrCreateSong сохраняет песню или возвращает существующую с той же парой группа+песня.
+	GetOrCreateSong(ctx context.Context, song *models.Song) (*models.Song, bool, error)
+	// GetAllSongs возвращает страницу песен (пагинация по номеру страницы).
+	GetAllSongs(ctx context.Context, filter SongFilter, sort SongSort, page int, limit int) ([]models.Song, int64, error)
+	// GetSongsAfter возвращает страницу песен после курсора (keyset-пагинация).
+	GetSongsAfter(ctx context.Context, filter SongFilter, sort SongSort, cursor *SongCursor, limit int) ([]models.Song, int64, error)
+	// GetSongByID возвращает активную песню по ID.
+	GetSongByID(ctx context.Context, id uint) (*models.Song, error)
+	// FindSongByGroupAndSong ищет активную песню по нормализованным названиям.
+	FindSongByGroupAndSong(ctx context.Context, group, song string) (*models.Song, error)
+	// FindSimilarSongs возвращает песни, похожие на запрошенную, начиная с самой похожей.
+	FindSimilarSongs(ctx context.Context, group, song string, limit int) ([]models.SongMatch, error)
+	// UpdateSong сохраняет изменения песни.
+	UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error)
+	// DeleteSong перемещает песню в корзину.
+	DeleteSong(ctx context.Context, id uint) error
+	// GetTrashedSongs возвращает страницу песен в корзине.
+	GetTrashedSongs(ctx context.Context, page int, limit int) ([]models.Song, int64, error)
+	// RestoreSong восстанавливает песню из корзины.
+	RestoreSong(ctx context.Context, id uint) (*models.Song, error)
+	// PurgeSong окончательно удаляет песню из корзины.
+	PurgeSong(ctx context.Context, id uint) error
+	// PurgeTrashedBefore окончательно удаляет песни, перемещенные в корзину раньше cutoff.
+	PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+var _ SongStore = (*SongRepository)(nil)
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"music-library/models"
+	"music-library/normalize"
+)
+
+// songTag — строка связующей таблицы песен и тегов.
+type songTag struct {
+	SongID int `gorm:"primaryKey"`
+	TagID  int `gorm:"primaryKey"`
+}
+
+// TableName задает имя таблицы для songTag.
+func (songTag) TableName() string {
+	return "song_tags"
+}
+
+// TagRepository предоставляет методы для работы с тегами в базе данных.
+type TagRepository struct {
+	DB *gorm.DB // Экземпляр базы данных GORM
+}
+
+// GetTags получает список тегов, упорядоченный по имени.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - name string: подстрока имени для фильтрации (пустая строка — без фильтра).
+//   - page int: номер страницы (начиная с 1).
+//   - limit int: количество записей на странице.
+//
+// Возвращает:
+//   - []models.Tag: список тегов.
+//   - int64: общее количество тегов, удовлетворяющих фильтру.
+//   - error: ошибка, если запрос не удался.
+func (repo *TagRepository) GetTags(ctx context.Context, name string, page int, limit int) ([]models.Tag, int64, error) {
+	query := func() *gorm.DB {
+		q := repo.DB.WithContext(ctx).Model(&models.Tag{})
+		if name != "" {
+			q = q.Where("name LIKE ? ESCAPE '\\'", containsPattern(normalize.Name(name)))
+		}
+		return q
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		log.Printf("ERROR: Failed to count tags. Error: %v\n", err)
+		return nil, 0, err
+	}
+
+	var tags []models.Tag
+	offset := (page - 1) * limit
+	if err := query().Order("name").Limit(limit).Offset(offset).Find(&tags).Error; err != nil {
+		log.Printf("ERROR: Failed to retrieve tags. Error: %v\n", err)
+		return nil, 0, err
+	}
+	return tags, total, nil
+}
+
+// DeleteTag удаляет тег у всех песен.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - id uint: ID тега.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если тег не найден, или ошибка удаления.
+func (repo *TagRepository) DeleteTag(ctx context.Context, id uint) error {
+	result := repo.DB.WithContext(ctx).Delete(&models.Tag{}, id)
+	if result.Error != nil {
+		log.Printf("ERROR: Failed to delete tag with ID: %d. Error: %v\n", id, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	log.Printf("INFO: Successfully deleted tag with ID: %d\n", id)
+	return nil
+}
+
+// SetSongTags заменяет набор тегов песни. Несуществующие теги создаются.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - songID uint: ID песни.
+//   - names []string: новый набор тегов (пустой — удалить все теги песни).
+//
+// Возвращает:
+//   - *models.Song: песня с обновленными жанрами и тегами.
+//   - error: gorm.ErrRecordNotFound, если песня не найдена, или ошибка сохранения.
+func (repo *TagRepository) SetSongTags(ctx context.Context, songID uint, names []string) (*models.Song, error) {
+	return repo.changeSongTags(ctx, songID, names, true)
+}
+
+// AddSongTags добавляет теги песне, сохраняя уже назначенные. Несуществующие теги создаются.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - songID uint: ID песни.
+//   - names []string: добавляемые теги.
+//
+// Возвращает:
+//   - *models.Song: песня с обновленными жанрами и тегами.
+//   - error: gorm.ErrRecordNotFound, если песня не найдена, или ошибка сохранения.
+func (repo *TagRepository) AddSongTags(ctx context.Context, songID uint, names []string) (*models.Song, error) {
+	return repo.changeSongTags(ctx, songID, names, false)
+}
+
+// RemoveSongTag снимает тег с песни. Сам тег остается в справочнике.
+//
+// Принимает:
+//   - ctx context.Context: контекст запроса.
+//   - songID uint: ID песни.
+//   - name string: имя тега.
+//
+// Возвращает:
+//   - error: gorm.ErrRecordNotFound, если у песни нет такого тега.
+func (repo *TagRepository) RemoveSongTag(ctx context.Context, songID uint, name string) error {
+	result := repo.DB.WithContext(ctx).
+		Where("song_id = ? AND tag_id IN (SELECT id FROM tags WHERE name = ?)", songID, normalize.Name(name)).
+		Delete(&songTag{})
+	if result.Error != nil {
+		log.Printf("ERROR: Failed to remove tag '%s' from song with ID: %d. Error: %v\n", name, songID, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// changeSongTags назначает песне теги; при replace = true прежние теги песни снимаются.
+func (repo *TagRepository) changeSongTags(ctx context.Context, songID uint, names []string, replace bool) (*models.Song, error) {
+	db := repo.DB.WithContext(ctx)
+	names = NormalizeTags(names)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&models.Song{}, songID).Error; err != nil {
+			return err
+		}
+		if replace {
+			if err := tx.Where("song_id = ?", songID).Delete(&songTag{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		tags := make([]models.Tag, 0, len(names))
+		for _, name := range names {
+			tags = append(tags, models.Tag{Name: name})
+		}
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+			Create(&tags).Error; err != nil {
+			return err
+		}
+
+		// ID тегов, созданных ранее (в том числе параллельными запросами), при ON CONFLICT не возвращаются
+		var ids []int
+		if err := tx.Model(&models.Tag{}).Where("name IN ?", names).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		rows := make([]songTag, 0, len(ids))
+		for _, id := range ids {
+			rows = append(rows, songTag{SongID: int(songID), TagID: id})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to change tags of song with ID: %d. Error: %v\n", songID, err)
+		return nil, err
+	}
+	return getSongWithCategories(db, songID)
+}
+
+// NormalizeTags нормализует имена тегов, отбрасывая пустые и повторяющиеся.
+func NormalizeTags(names []string) []string {
+	seen := make(map[string]struct{}, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		key := normalize.Name(name)
+		if _, ok := seen[key]; ok || key == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"music-library/config"
+	"net"
+	"net/http"
+	"time"
+)
+
+// New создает HTTP-сервер с адресом и таймаутами из конфигурации.
+func New(cfg config.HTTPConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+}
+
+// Run запускает srv и блокируется до отмены контекста или ошибки сервера.
+//
+// Адрес занимается до возврата управления в цикл обработки, поэтому ошибка запуска
+// (например, занятый порт) возвращается сразу. После отмены ctx сервер перестает принимать
+// новые соединения и ждет завершения обрабатываемых запросов не дольше shutdownTimeout;
+// оставшиеся соединения закрываются принудительно.
+//
+// Принимает:
+//   - ctx context.Context: контекст, отмена которого запускает остановку сервера.
+//   - srv *http.Server: сервер с заполненными Addr и Handler.
+//   - shutdownTimeout time.Duration: время на завершение обрабатываемых запросов.
+//
+// Возвращает:
+//   - error: ошибка запуска или работы сервера; ошибка, если запросы не завершились вовремя;
+//     nil при штатной остановке.
+func Run(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", srv.Addr, err)
+	}
+	log.Printf("INFO: HTTP server listening on %s", listener.Addr())
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// Сервер остановился без запроса на остановку
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Printf("INFO: Shutting down the HTTP server, waiting up to %s for in-flight requests...", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Не все запросы завершились вовремя: закрываем оставшиеся соединения
+		closeErr := srv.Close()
+		return errors.Join(fmt.Errorf("HTTP server shutdown: %w", err), closeErr)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+	log.Println("INFO: HTTP server stopped.")
+	return nil
+}